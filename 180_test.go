@@ -0,0 +1,131 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard180V1s = []Standard180V1{
+		Standard180V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard180V1Transaction{
+				ReturnActionCode:            Standard180V1ActionAuthorization,
+				RMANumber:                   "RMA-1001",
+				VendorID:                    "707738",
+				PurchaserAccountID:          "12345",
+				StoreID:                     "8976",
+				OriginalPurchaseOrderNumber: "12345678",
+				OriginalShipmentNumber:      "987",
+				ReturnFromCompanyName:       "Overlook Hotel",
+				ReturnFromContactName:       "Jack Torrance",
+				ReturnFromAddress1:          "333 E Wonderview Ave",
+				ReturnFromCityName:          "Estes Park",
+				ReturnFromStateCode:         "CO",
+				ReturnFromPostalCode:        "80517",
+			},
+			LineItems: []Standard180V1LineItem{
+				Standard180V1LineItem{
+					ItemIdentificationGTIN: "00821780002660",
+					QuantityReturned:       3,
+					QuantityAuthorized:     3,
+					ReturnReasonCode:       Standard180V1ReasonDamaged,
+					DispositionCode:        Standard180V1DispositionDestroy,
+				},
+				Standard180V1LineItem{
+					ItemIdentificationGTIN: "00821780002799",
+					QuantityReturned:       2,
+					QuantityAuthorized:     1,
+					ReturnReasonCode:       Standard180V1ReasonWrongItem,
+					DispositionCode:        Standard180V1DispositionRestock,
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard180V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard180V1Key, Standard180V1 := range Standard180V1s {
+		byteArrayPointer, err := Standard180V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/180-"+strconv.Itoa(Standard180V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 5, Standard180V1.Trailer.TotalQuantityReturned)
+		assert.Equal(t, 4, Standard180V1.Trailer.TotalQuantityAuthorized)
+	}
+
+}
+
+func TestStandard180V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/180-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard180V1 Standard180V1
+	err := standard180V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "RMA-1001", standard180V1.Transaction.RMANumber)
+	assert.Equal(t, "12345678", standard180V1.Transaction.OriginalPurchaseOrderNumber)
+	if assert.Len(t, standard180V1.LineItems, 2) {
+		assert.Equal(t, Standard180V1ReasonWrongItem, standard180V1.LineItems[1].ReturnReasonCode)
+		assert.Equal(t, Standard180V1DispositionRestock, standard180V1.LineItems[1].DispositionCode)
+	}
+
+	report := standard180V1.Validate(ctx)
+	assert.True(t, report.Valid())
+
+}
+
+func TestStandard180V1Validate(t *testing.T) {
+
+	ctx := context.Background()
+
+	standard180V1 := Standard180V1{
+		Transaction: Standard180V1Transaction{
+			ReturnActionCode: Standard180V1ActionAuthorization,
+		},
+		LineItems: []Standard180V1LineItem{
+			Standard180V1LineItem{
+				ItemIdentificationGTIN: "00821780002660",
+				QuantityReturned:       1,
+				QuantityAuthorized:     2,
+				ReturnReasonCode:       "ZZ",
+			},
+		},
+	}
+
+	report := standard180V1.Validate(ctx)
+	assert.False(t, report.Valid())
+
+	var fields []string
+	for _, validationError := range report.Errors {
+		fields = append(fields, validationError.Field)
+	}
+	assert.Equal(t, []string{"RMANumber", "OriginalPurchaseOrderNumber", "QuantityAuthorized", "ReturnReasonCode", "DispositionCode"}, fields)
+
+}
@@ -0,0 +1,510 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard180V1ActionRequest       = "RQ"
+	Standard180V1ActionAuthorization = "AU"
+)
+
+// Standard180V1Actions lists the return action codes.
+var Standard180V1Actions = []string{Standard180V1ActionRequest, Standard180V1ActionAuthorization}
+
+const (
+	Standard180V1ReasonDamaged   = "DM"
+	Standard180V1ReasonDefective = "DF"
+	Standard180V1ReasonWrongItem = "WI"
+	Standard180V1ReasonOverstock = "OS"
+)
+
+// Standard180V1Reasons lists the return reason codes.
+var Standard180V1Reasons = []string{Standard180V1ReasonDamaged, Standard180V1ReasonDefective, Standard180V1ReasonWrongItem, Standard180V1ReasonOverstock}
+
+const (
+	Standard180V1DispositionRestock = "RS"
+	Standard180V1DispositionRepair  = "RP"
+	Standard180V1DispositionDestroy = "DS"
+)
+
+// Standard180V1Dispositions lists the disposition codes.
+var Standard180V1Dispositions = []string{Standard180V1DispositionRestock, Standard180V1DispositionRepair, Standard180V1DispositionDestroy}
+
+type Standard180V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard180V1Transaction
+	LineItems         []Standard180V1LineItem
+	Trailer           Standard180V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard180V1Transaction struct {
+	Header                      string
+	TransactionType             string
+	TransactionSetPurpose       string
+	VersionNumber               string
+	ReturnActionCode            string
+	RMANumber                   string
+	RMADate                     string
+	RMATime                     string
+	AuthorizationExpirationDate string
+	VendorID                    string
+	PurchaserAccountID          string
+	StoreID                     string
+	OriginalPurchaseOrderNumber string
+	OriginalShipmentNumber      string
+	ReturnFromCompanyName       string
+	ReturnFromContactName       string
+	ReturnFromAddress1          string
+	ReturnFromAddress2          string
+	ReturnFromCityName          string
+	ReturnFromStateCode         string
+	ReturnFromPostalCode        string
+	ReturnFromCountryCode       string
+	CarrierRoutingDetails       string
+}
+
+type Standard180V1LineItem struct {
+	DetailSectionLoopA            string
+	LineItemNumber                int
+	ItemIdentificationGTIN        string
+	MasterStyle                   string
+	ColorCode                     string
+	SizeCode                      string
+	UnitOrBasisForMeasurementCode string
+	QuantityReturned              int
+	QuantityAuthorized            int
+	ReturnReasonCode              string
+	DispositionCode               string
+}
+
+type Standard180V1Trailer struct {
+	TrailerRecord           string
+	RecordCount             int
+	TotalQuantityReturned   int
+	TotalQuantityAuthorized int
+}
+
+func (s *Standard180V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "180"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "180"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	if s.Transaction.ReturnActionCode == "" {
+		s.Transaction.ReturnActionCode = Standard180V1ActionRequest
+	}
+	s.Transaction.RMADate = time.Now().Format("20060102")
+	s.Transaction.RMATime = time.Now().Format("150405")
+
+	// Line Items
+	var totalQuantityReturned, totalQuantityAuthorized int
+	for lineItemKey, lineItem := range s.LineItems {
+		s.LineItems[lineItemKey].DetailSectionLoopA = "02"
+		s.LineItems[lineItemKey].LineItemNumber = lineItemKey + 1
+		if lineItem.UnitOrBasisForMeasurementCode == "" {
+			s.LineItems[lineItemKey].UnitOrBasisForMeasurementCode = "EA"
+		}
+		totalQuantityReturned += lineItem.QuantityReturned
+		totalQuantityAuthorized += lineItem.QuantityAuthorized
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.LineItems)
+	s.Trailer.TotalQuantityReturned = totalQuantityReturned
+	s.Trailer.TotalQuantityAuthorized = totalQuantityAuthorized
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard180V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		errLineItem := enc.Encode(lineItem)
+		if errLineItem != nil {
+			return nil, errLineItem
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard180V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard180V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard180V1LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.LineItems = append(s.LineItems, x)
+		case "09":
+			var x Standard180V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+func (s *Standard180V1) Validate(ctx context.Context) ValidationReport {
+
+	report := ValidationReport{
+		InterchangeID:       s.EnvelopeHeaderV3.InterchangeID,
+		TransactionType:     "180",
+		PurchaseOrderNumber: s.Transaction.OriginalPurchaseOrderNumber,
+	}
+
+	// Transaction
+	authorization := s.Transaction.ReturnActionCode == Standard180V1ActionAuthorization
+	switch s.Transaction.ReturnActionCode {
+	case "", Standard180V1ActionRequest, Standard180V1ActionAuthorization:
+	default:
+		report.Errors = append(report.Errors, ValidationError{RecordType: "01", Field: "ReturnActionCode", Code: ValidationCodeInvalid, Message: fmt.Sprintf("unknown return action code %s", s.Transaction.ReturnActionCode)})
+	}
+	if authorization && s.Transaction.RMANumber == "" {
+		report.Errors = append(report.Errors, ValidationError{RecordType: "01", Field: "RMANumber", Code: ValidationCodeMissing, Message: "authorization has no RMA number"})
+	}
+	if s.Transaction.OriginalPurchaseOrderNumber == "" && s.Transaction.OriginalShipmentNumber == "" {
+		report.Errors = append(report.Errors, ValidationError{RecordType: "01", Field: "OriginalPurchaseOrderNumber", Code: ValidationCodeMissing, Message: "return references no purchase order or shipment"})
+	}
+	if len(s.LineItems) == 0 {
+		report.Errors = append(report.Errors, ValidationError{RecordType: "02", Code: ValidationCodeMissing, Message: "return has no line items"})
+	}
+
+	// Line Items
+	for lineItemKey, lineItem := range s.LineItems {
+		lineItemNumber := lineItem.LineItemNumber
+		if lineItemNumber == 0 {
+			lineItemNumber = lineItemKey + 1
+		}
+		if lineItem.ItemIdentificationGTIN == "" {
+			report.Errors = append(report.Errors, ValidationError{RecordType: "02", LineItemNumber: lineItemNumber, Field: "ItemIdentificationGTIN", Code: ValidationCodeMissing, Message: "line item has no GTIN"})
+		}
+		if lineItem.QuantityReturned <= 0 {
+			report.Errors = append(report.Errors, ValidationError{RecordType: "02", LineItemNumber: lineItemNumber, Field: "QuantityReturned", Code: ValidationCodeInvalid, Message: "quantity returned must be positive"})
+		}
+		if lineItem.QuantityAuthorized < 0 || lineItem.QuantityAuthorized > lineItem.QuantityReturned {
+			report.Errors = append(report.Errors, ValidationError{RecordType: "02", LineItemNumber: lineItemNumber, Field: "QuantityAuthorized", Code: ValidationCodeInvalid, Message: "quantity authorized exceeds quantity returned"})
+		}
+		switch lineItem.ReturnReasonCode {
+		case Standard180V1ReasonDamaged, Standard180V1ReasonDefective, Standard180V1ReasonWrongItem, Standard180V1ReasonOverstock:
+		case "":
+			report.Errors = append(report.Errors, ValidationError{RecordType: "02", LineItemNumber: lineItemNumber, Field: "ReturnReasonCode", Code: ValidationCodeMissing, Message: "line item has no return reason"})
+		default:
+			report.Errors = append(report.Errors, ValidationError{RecordType: "02", LineItemNumber: lineItemNumber, Field: "ReturnReasonCode", Code: ValidationCodeInvalid, Message: fmt.Sprintf("unknown return reason code %s", lineItem.ReturnReasonCode)})
+		}
+		switch lineItem.DispositionCode {
+		case Standard180V1DispositionRestock, Standard180V1DispositionRepair, Standard180V1DispositionDestroy:
+		case "":
+			if authorization {
+				report.Errors = append(report.Errors, ValidationError{RecordType: "02", LineItemNumber: lineItemNumber, Field: "DispositionCode", Code: ValidationCodeMissing, Message: "authorized line item has no disposition"})
+			}
+		default:
+			report.Errors = append(report.Errors, ValidationError{RecordType: "02", LineItemNumber: lineItemNumber, Field: "DispositionCode", Code: ValidationCodeInvalid, Message: fmt.Sprintf("unknown disposition code %s", lineItem.DispositionCode)})
+		}
+	}
+
+	return report
+}
+
+func (s *Standard180V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.ReturnActionCode = req[4]
+	}
+	if len(req) > 5 {
+		s.RMANumber = req[5]
+	}
+	if len(req) > 6 {
+		s.RMADate = req[6]
+	}
+	if len(req) > 7 {
+		s.RMATime = req[7]
+	}
+	if len(req) > 8 {
+		s.AuthorizationExpirationDate = req[8]
+	}
+	if len(req) > 9 {
+		s.VendorID = req[9]
+	}
+	if len(req) > 10 {
+		s.PurchaserAccountID = req[10]
+	}
+	if len(req) > 11 {
+		s.StoreID = req[11]
+	}
+	if len(req) > 12 {
+		s.OriginalPurchaseOrderNumber = req[12]
+	}
+	if len(req) > 13 {
+		s.OriginalShipmentNumber = req[13]
+	}
+	if len(req) > 14 {
+		s.ReturnFromCompanyName = req[14]
+	}
+	if len(req) > 15 {
+		s.ReturnFromContactName = req[15]
+	}
+	if len(req) > 16 {
+		s.ReturnFromAddress1 = req[16]
+	}
+	if len(req) > 17 {
+		s.ReturnFromAddress2 = req[17]
+	}
+	if len(req) > 18 {
+		s.ReturnFromCityName = req[18]
+	}
+	if len(req) > 19 {
+		s.ReturnFromStateCode = req[19]
+	}
+	if len(req) > 20 {
+		s.ReturnFromPostalCode = req[20]
+	}
+	if len(req) > 21 {
+		s.ReturnFromCountryCode = req[21]
+	}
+	if len(req) > 22 {
+		s.CarrierRoutingDetails = req[22]
+	}
+
+	return nil
+}
+
+func (s *Standard180V1LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.ItemIdentificationGTIN = req[2]
+	}
+	if len(req) > 3 {
+		s.MasterStyle = req[3]
+	}
+	if len(req) > 4 {
+		s.ColorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.SizeCode = req[5]
+	}
+	if len(req) > 6 {
+		s.UnitOrBasisForMeasurementCode = req[6]
+	}
+	if len(req) > 7 {
+		if req[7] != "" {
+			quantityReturned, err := strconv.Atoi(req[7])
+			if err != nil {
+				return err
+			}
+			s.QuantityReturned = quantityReturned
+		}
+	}
+	if len(req) > 8 {
+		if req[8] != "" {
+			quantityAuthorized, err := strconv.Atoi(req[8])
+			if err != nil {
+				return err
+			}
+			s.QuantityAuthorized = quantityAuthorized
+		}
+	}
+	if len(req) > 9 {
+		s.ReturnReasonCode = req[9]
+	}
+	if len(req) > 10 {
+		s.DispositionCode = req[10]
+	}
+
+	return nil
+}
+
+func (s *Standard180V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			totalQuantityReturned, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityReturned = totalQuantityReturned
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			totalQuantityAuthorized, err := strconv.Atoi(req[3])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityAuthorized = totalQuantityAuthorized
+		}
+	}
+
+	return nil
+}
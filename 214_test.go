@@ -0,0 +1,166 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard214V1s = []Standard214V1{
+		Standard214V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard214V1Transaction{
+				CarrierRoutingDetails: "FEDL",
+				ShipmentNumber:        "987",
+				BOLNumber:             "33996",
+			},
+			Events: []Standard214V1Event{
+				Standard214V1Event{
+					StatusCode: Standard214V1StatusPickedUp,
+					StatusDate: "20210226",
+					StatusTime: "090000",
+					CityName:   "Charlotte",
+					StateCode:  "NC",
+				},
+				Standard214V1Event{
+					CarrierTrackingNumber: "1Z5R9A10341241218",
+					StatusCode:            Standard214V1StatusDelivered,
+					StatusDate:            "20210301",
+					StatusTime:            "141500",
+					CityName:              "Estes Park",
+					StateCode:             "CO",
+					SignedBy:              "TORRANCE",
+				},
+				Standard214V1Event{
+					CarrierTrackingNumber: "1Z5R9A10341241225",
+					StatusCode:            Standard214V1StatusException,
+					StatusReasonCode:      "A1",
+					StatusDate:            "20210228",
+					StatusTime:            "060000",
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard214V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard214V1Key, Standard214V1 := range Standard214V1s {
+		byteArrayPointer, err := Standard214V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/214-"+strconv.Itoa(Standard214V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, Standard214V1.Trailer.RecordCount)
+	}
+
+}
+
+func TestStandard214V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/214-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard214V1 Standard214V1
+	err := standard214V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "33996", standard214V1.Transaction.BOLNumber)
+	if assert.Len(t, standard214V1.Events, 3) {
+		assert.Equal(t, Standard214V1StatusDelivered, standard214V1.Events[1].StatusCode)
+		assert.Equal(t, "TORRANCE", standard214V1.Events[1].SignedBy)
+	}
+
+}
+
+func TestStandard214V1PurchaseOrderStatus(t *testing.T) {
+
+	ctx := context.Background()
+
+	asn := Standard856V7{
+		Transaction: Standard856V7Transaction{
+			BOLNumber: "33996",
+		},
+		Pallets: []Standard856V7Pallet{
+			Standard856V7Pallet{
+				PalletID: "107077380000002807",
+				Shipments: []Standard856V7Shipment{
+					Standard856V7Shipment{CarrierTrackingNumber: "1Z5R9A10341241218", BuyersPurchaseOrderNumber: "W1044"},
+					Standard856V7Shipment{CarrierTrackingNumber: "1Z5R9A10341241225", BuyersPurchaseOrderNumber: "W1045"},
+				},
+			},
+		},
+	}
+
+	statuses := Standard214V1s[0].Correlate(ctx, asn)
+	if assert.Len(t, statuses, 2) {
+		assert.Len(t, statuses[0].Events, 2)
+		assert.True(t, statuses[0].Delivered)
+		assert.Equal(t, Standard214V1StatusException, statuses[1].StatusCode)
+	}
+
+	purchaseOrderStatuses := Standard214V1s[0].PurchaseOrderStatus(ctx, []Standard856V7{asn})
+	if assert.Len(t, purchaseOrderStatuses, 2) {
+		assert.Equal(t, "W1044", purchaseOrderStatuses[0].PurchaseOrderNumber)
+		assert.True(t, purchaseOrderStatuses[0].Delivered)
+		assert.False(t, purchaseOrderStatuses[1].Delivered)
+		assert.Equal(t, Standard214V1StatusException, purchaseOrderStatuses[1].StatusCode)
+	}
+
+	// Line Items
+	asn.Pallets[0].Shipments[1].BuyersPurchaseOrderNumber = ""
+	asn.Pallets[0].Shipments[1].LineItems = []Standard856V7LineItem{
+		Standard856V7LineItem{ItemIdentificationGTIN: "00707738003265", QuantityShipped: 6, BuyersPurchaseOrderNumber: "W1046"},
+		Standard856V7LineItem{ItemIdentificationGTIN: "00707738001245", QuantityShipped: 6, BuyersPurchaseOrderNumber: "W1047"},
+	}
+	purchaseOrderStatuses = Standard214V1s[0].PurchaseOrderStatus(ctx, []Standard856V7{asn})
+	if assert.Len(t, purchaseOrderStatuses, 3) {
+		assert.Equal(t, "W1046", purchaseOrderStatuses[1].PurchaseOrderNumber)
+		assert.Equal(t, "W1047", purchaseOrderStatuses[2].PurchaseOrderNumber)
+		assert.Equal(t, Standard214V1StatusException, purchaseOrderStatuses[2].StatusCode)
+		assert.Len(t, purchaseOrderStatuses[2].Shipments, 1)
+	}
+
+	// Purchase Order
+	standard214V1 := Standard214V1{
+		Transaction: Standard214V1Transaction{
+			BOLNumber: "33996",
+		},
+		Events: []Standard214V1Event{
+			Standard214V1Event{
+				BuyersPurchaseOrderNumber: "W1047",
+				StatusCode:                Standard214V1StatusInTransit,
+				StatusDate:                "20210227",
+				StatusTime:                "120000",
+			},
+		},
+	}
+	statuses = standard214V1.Correlate(ctx, asn)
+	if assert.Len(t, statuses, 2) {
+		assert.Empty(t, statuses[0].Events)
+		assert.Equal(t, Standard214V1StatusInTransit, statuses[1].StatusCode)
+	}
+
+}
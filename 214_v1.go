@@ -0,0 +1,497 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard214V1StatusPickedUp  = "AF"
+	Standard214V1StatusInTransit = "X6"
+	Standard214V1StatusDelivered = "D1"
+	Standard214V1StatusException = "SD"
+)
+
+// Standard214V1Statuses lists the shipment status codes.
+var Standard214V1Statuses = []string{Standard214V1StatusPickedUp, Standard214V1StatusInTransit, Standard214V1StatusDelivered, Standard214V1StatusException}
+
+type Standard214V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard214V1Transaction
+	Events            []Standard214V1Event
+	Trailer           Standard214V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard214V1Transaction struct {
+	Header                string
+	TransactionType       string
+	TransactionSetPurpose string
+	VersionNumber         string
+	ReferenceNumber       string
+	ReportDate            string
+	ReportTime            string
+	CarrierRoutingDetails string
+	ShipmentNumber        string
+	BOLNumber             string
+	VendorID              string
+}
+
+type Standard214V1Event struct {
+	DetailSectionLoopA        string
+	LineItemNumber            int
+	CarrierTrackingNumber     string
+	BOLNumber                 string
+	BuyersPurchaseOrderNumber string
+	StatusCode                string
+	StatusReasonCode          string
+	StatusDate                string
+	StatusTime                string
+	TimeZone                  string
+	CityName                  string
+	StateCode                 string
+	CountryCode               string
+	SignedBy                  string
+}
+
+type Standard214V1Trailer struct {
+	TrailerRecord string
+	RecordCount   int
+}
+
+type Standard214V1ShipmentStatus struct {
+	PalletID   string
+	Shipment   Standard856V7Shipment
+	Events     []Standard214V1Event
+	StatusCode string
+	Delivered  bool
+}
+
+type Standard214V1PurchaseOrderStatus struct {
+	PurchaseOrderNumber string
+	Shipments           []Standard214V1ShipmentStatus
+	StatusCode          string
+	Delivered           bool
+}
+
+func (s *Standard214V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "214"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "214"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	s.Transaction.ReportDate = time.Now().Format("20060102")
+	s.Transaction.ReportTime = time.Now().Format("150405")
+
+	// Events
+	for eventKey := range s.Events {
+		s.Events[eventKey].DetailSectionLoopA = "02"
+		s.Events[eventKey].LineItemNumber = eventKey + 1
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.Events)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard214V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Events
+	for _, event := range s.Events {
+		errEvent := enc.Encode(event)
+		if errEvent != nil {
+			return nil, errEvent
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard214V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard214V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard214V1Event
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Events = append(s.Events, x)
+		case "09":
+			var x Standard214V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// Correlate attaches the status events to the shipments of the ship notice.
+// An event with a tracking number belongs to the shipment with that carrier
+// tracking number, an event with a purchase order number to the shipments
+// carrying that purchase order, otherwise an event whose bill of lading
+// matches the ship notice belongs to every shipment on it. Events are ordered
+// by date and time.
+func (s *Standard214V1) Correlate(ctx context.Context, asn Standard856V7) []Standard214V1ShipmentStatus {
+
+	var statuses []Standard214V1ShipmentStatus
+	for _, pallet := range asn.Pallets {
+		for _, shipment := range pallet.Shipments {
+
+			status := Standard214V1ShipmentStatus{
+				PalletID: pallet.PalletID,
+				Shipment: shipment,
+			}
+			purchaseOrderNumbers := standard214V1PurchaseOrderNumbers(shipment)
+			for _, event := range s.Events {
+				bolNumber := event.BOLNumber
+				if bolNumber == "" {
+					bolNumber = s.Transaction.BOLNumber
+				}
+				switch {
+				case event.CarrierTrackingNumber != "":
+					if event.CarrierTrackingNumber == shipment.CarrierTrackingNumber {
+						status.Events = append(status.Events, event)
+					}
+				case event.BuyersPurchaseOrderNumber != "":
+					for _, purchaseOrderNumber := range purchaseOrderNumbers {
+						if event.BuyersPurchaseOrderNumber == purchaseOrderNumber {
+							status.Events = append(status.Events, event)
+							break
+						}
+					}
+				case bolNumber != "" && bolNumber == asn.Transaction.BOLNumber:
+					status.Events = append(status.Events, event)
+				}
+			}
+
+			// Latest
+			sort.SliceStable(status.Events, func(i, j int) bool {
+				return status.Events[i].StatusDate+status.Events[i].StatusTime < status.Events[j].StatusDate+status.Events[j].StatusTime
+			})
+			if len(status.Events) > 0 {
+				status.StatusCode = status.Events[len(status.Events)-1].StatusCode
+				status.Delivered = status.StatusCode == Standard214V1StatusDelivered
+			}
+
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses
+}
+
+// PurchaseOrderStatus groups the correlated shipments of the ship notices by
+// purchase order. A purchase order is delivered once all of its shipments are,
+// and a shipment whose lines carry several purchase orders counts toward each
+// of them.
+func (s *Standard214V1) PurchaseOrderStatus(ctx context.Context, asns []Standard856V7) []Standard214V1PurchaseOrderStatus {
+
+	var purchaseOrderStatuses []Standard214V1PurchaseOrderStatus
+	purchaseOrderKeys := map[string]int{}
+	for _, asn := range asns {
+		for _, status := range s.Correlate(ctx, asn) {
+			for _, purchaseOrderNumber := range standard214V1PurchaseOrderNumbers(status.Shipment) {
+				purchaseOrderKey, ok := purchaseOrderKeys[purchaseOrderNumber]
+				if !ok {
+					purchaseOrderStatuses = append(purchaseOrderStatuses, Standard214V1PurchaseOrderStatus{
+						PurchaseOrderNumber: purchaseOrderNumber,
+						Delivered:           true,
+					})
+					purchaseOrderKey = len(purchaseOrderStatuses) - 1
+					purchaseOrderKeys[purchaseOrderNumber] = purchaseOrderKey
+				}
+				purchaseOrderStatuses[purchaseOrderKey].Shipments = append(purchaseOrderStatuses[purchaseOrderKey].Shipments, status)
+				if !status.Delivered {
+					purchaseOrderStatuses[purchaseOrderKey].Delivered = false
+				}
+			}
+		}
+	}
+
+	// Status
+	for purchaseOrderKey, purchaseOrderStatus := range purchaseOrderStatuses {
+		var latest string
+		for _, status := range purchaseOrderStatus.Shipments {
+			if len(status.Events) == 0 {
+				continue
+			}
+			event := status.Events[len(status.Events)-1]
+			if event.StatusDate+event.StatusTime >= latest {
+				latest = event.StatusDate + event.StatusTime
+				purchaseOrderStatuses[purchaseOrderKey].StatusCode = event.StatusCode
+			}
+		}
+		if purchaseOrderStatus.Delivered {
+			purchaseOrderStatuses[purchaseOrderKey].StatusCode = Standard214V1StatusDelivered
+		}
+	}
+
+	return purchaseOrderStatuses
+}
+
+// standard214V1PurchaseOrderNumbers returns the distinct purchase orders a
+// shipment carries, in the order its line items name them. A line item without
+// a purchase order number, or a shipment without line items, falls back to the
+// shipment's purchase order number.
+func standard214V1PurchaseOrderNumbers(shipment Standard856V7Shipment) []string {
+
+	var purchaseOrderNumbers []string
+	seen := map[string]bool{}
+	for _, lineItem := range shipment.LineItems {
+		purchaseOrderNumber := lineItem.BuyersPurchaseOrderNumber
+		if purchaseOrderNumber == "" {
+			purchaseOrderNumber = shipment.BuyersPurchaseOrderNumber
+		}
+		if !seen[purchaseOrderNumber] {
+			seen[purchaseOrderNumber] = true
+			purchaseOrderNumbers = append(purchaseOrderNumbers, purchaseOrderNumber)
+		}
+	}
+	if len(purchaseOrderNumbers) == 0 {
+		purchaseOrderNumbers = append(purchaseOrderNumbers, shipment.BuyersPurchaseOrderNumber)
+	}
+
+	return purchaseOrderNumbers
+}
+
+func (s *Standard214V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.ReferenceNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.ReportDate = req[5]
+	}
+	if len(req) > 6 {
+		s.ReportTime = req[6]
+	}
+	if len(req) > 7 {
+		s.CarrierRoutingDetails = req[7]
+	}
+	if len(req) > 8 {
+		s.ShipmentNumber = req[8]
+	}
+	if len(req) > 9 {
+		s.BOLNumber = req[9]
+	}
+	if len(req) > 10 {
+		s.VendorID = req[10]
+	}
+
+	return nil
+}
+
+func (s *Standard214V1Event) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.CarrierTrackingNumber = req[2]
+	}
+	if len(req) > 3 {
+		s.BOLNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.BuyersPurchaseOrderNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.StatusCode = req[5]
+	}
+	if len(req) > 6 {
+		s.StatusReasonCode = req[6]
+	}
+	if len(req) > 7 {
+		s.StatusDate = req[7]
+	}
+	if len(req) > 8 {
+		s.StatusTime = req[8]
+	}
+	if len(req) > 9 {
+		s.TimeZone = req[9]
+	}
+	if len(req) > 10 {
+		s.CityName = req[10]
+	}
+	if len(req) > 11 {
+		s.StateCode = req[11]
+	}
+	if len(req) > 12 {
+		s.CountryCode = req[12]
+	}
+	if len(req) > 13 {
+		s.SignedBy = req[13]
+	}
+
+	return nil
+}
+
+func (s *Standard214V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+
+	return nil
+}
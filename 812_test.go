@@ -0,0 +1,106 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard812V1s = []Standard812V1{
+		Standard812V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard812V1Transaction{
+				MemoNumber:          "CM-2001",
+				InvoiceNumber:       "INV-2",
+				InvoiceDate:         "20210220",
+				PurchaseOrderNumber: "12345679",
+				CurrencyCode:        "USD",
+				VendorID:            "707738",
+				PurchaserAccountID:  "12345",
+			},
+			LineItems: []Standard812V1LineItem{
+				Standard812V1LineItem{
+					PurchaseOrderLineItemNumber: "1",
+					ItemIdentificationGTIN:      "00821780002660",
+					AdjustmentReasonCode:        AdjustmentReasonItemNotAcceptedDamaged,
+					AdjustmentAmount:            -1250,
+					AdjustmentQuantity:          5,
+					UnitPrice:                   250,
+					AdjustmentDescription:       "Crushed cartons",
+				},
+				Standard812V1LineItem{
+					PurchaseOrderLineItemNumber: "2",
+					ItemIdentificationGTIN:      "00821780002799",
+					AdjustmentReasonCode:        AdjustmentReasonPricingError,
+					AdjustmentAmount:            300,
+					AdjustmentDescription:       "Price below contract",
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard812V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard812V1Key, Standard812V1 := range Standard812V1s {
+		byteArrayPointer, err := Standard812V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/812-"+strconv.Itoa(Standard812V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, -950, Standard812V1.Transaction.TotalMemoAmount)
+		assert.Equal(t, Standard812V1Credit, Standard812V1.Transaction.CreditDebitFlag)
+		assert.Equal(t, "-12.5000", Standard812V1.LineItems[0].AdjustmentAmountFormatted)
+		assert.Equal(t, Standard812V1Debit, Standard812V1.LineItems[1].CreditDebitFlag)
+	}
+
+}
+
+func TestStandard812V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/812-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard812V1 Standard812V1
+	err := standard812V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "INV-2", standard812V1.Transaction.InvoiceNumber)
+	assert.Equal(t, -950, standard812V1.Transaction.TotalMemoAmount)
+	if assert.Len(t, standard812V1.LineItems, 2) {
+		assert.Equal(t, -1250, standard812V1.LineItems[0].AdjustmentAmount)
+		assert.Equal(t, "1", standard812V1.LineItems[0].PurchaseOrderLineItemNumber)
+		assert.Equal(t, 300, standard812V1.LineItems[1].AdjustmentAmount)
+	}
+	assert.Equal(t, -1250, standard812V1.Trailer.TotalCreditAmount)
+	assert.Equal(t, 300, standard812V1.Trailer.TotalDebitAmount)
+	assert.Equal(t, -950, standard812V1.Trailer.NetAdjustmentAmount)
+
+	var lineItem Standard812V1LineItem
+	err = lineItem.FromSlice(ctx, []string{"02", "1", "", "", "01", "C", "12.50"})
+	assert.Nil(t, err)
+	assert.Equal(t, -1250, lineItem.AdjustmentAmount)
+
+}
@@ -0,0 +1,458 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard812V1Credit = "C"
+	Standard812V1Debit  = "D"
+)
+
+// Standard812V1CreditDebitFlags lists the credit and debit flags.
+var Standard812V1CreditDebitFlags = []string{Standard812V1Credit, Standard812V1Debit}
+
+type Standard812V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard812V1Transaction
+	LineItems         []Standard812V1LineItem
+	Trailer           Standard812V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard812V1Transaction struct {
+	Header                   string
+	TransactionType          string
+	TransactionSetPurpose    string
+	VersionNumber            string
+	MemoNumber               string
+	MemoDate                 string
+	CreditDebitFlag          string
+	InvoiceNumber            string
+	InvoiceDate              string
+	PurchaseOrderNumber      string
+	PODate                   string
+	CurrencyCode             string
+	TotalMemoAmount          int `csv:"-"`
+	TotalMemoAmountFormatted string
+	VendorID                 string
+	PurchaserAccountID       string
+}
+
+type Standard812V1LineItem struct {
+	DetailSectionLoopA          string
+	LineItemNumber              int
+	PurchaseOrderLineItemNumber string
+	ItemIdentificationGTIN      string
+	AdjustmentReasonCode        string
+	CreditDebitFlag             string
+	AdjustmentAmount            int `csv:"-"`
+	AdjustmentAmountFormatted   string
+	AdjustmentQuantity          int
+	UnitOfMeasure               string
+	UnitPrice                   int `csv:"-"`
+	UnitPriceFormatted          string
+	AdjustmentDescription       string
+}
+
+type Standard812V1Trailer struct {
+	TrailerRecord                string
+	RecordCount                  int
+	TotalCreditAmount            int `csv:"-"`
+	TotalCreditAmountFormatted   string
+	TotalDebitAmount             int `csv:"-"`
+	TotalDebitAmountFormatted    string
+	NetAdjustmentAmount          int `csv:"-"`
+	NetAdjustmentAmountFormatted string
+}
+
+// Credits are carried as negative amounts and debits as positive amounts; the
+// credit/debit flags are derived from the sign when the memo is prepared.
+func (s *Standard812V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "812"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "812"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	if s.Transaction.MemoDate == "" {
+		s.Transaction.MemoDate = time.Now().Format("20060102")
+	}
+
+	// Line Items
+	var totalCreditAmount, totalDebitAmount int
+	for lineItemKey, lineItem := range s.LineItems {
+		s.LineItems[lineItemKey].DetailSectionLoopA = "02"
+		s.LineItems[lineItemKey].LineItemNumber = lineItemKey + 1
+		s.LineItems[lineItemKey].CreditDebitFlag = standard812V1Flag(lineItem.AdjustmentAmount)
+		s.LineItems[lineItemKey].AdjustmentAmountFormatted = formatAmount(lineItem.AdjustmentAmount)
+		s.LineItems[lineItemKey].UnitPriceFormatted = formatAmount(lineItem.UnitPrice)
+		if lineItem.UnitOfMeasure == "" && lineItem.AdjustmentQuantity != 0 {
+			s.LineItems[lineItemKey].UnitOfMeasure = "EA"
+		}
+		if lineItem.AdjustmentAmount < 0 {
+			totalCreditAmount += lineItem.AdjustmentAmount
+		} else {
+			totalDebitAmount += lineItem.AdjustmentAmount
+		}
+	}
+	s.Transaction.TotalMemoAmount = totalCreditAmount + totalDebitAmount
+	s.Transaction.TotalMemoAmountFormatted = formatAmount(s.Transaction.TotalMemoAmount)
+	s.Transaction.CreditDebitFlag = standard812V1Flag(s.Transaction.TotalMemoAmount)
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.LineItems)
+	s.Trailer.TotalCreditAmount = totalCreditAmount
+	s.Trailer.TotalCreditAmountFormatted = formatAmount(totalCreditAmount)
+	s.Trailer.TotalDebitAmount = totalDebitAmount
+	s.Trailer.TotalDebitAmountFormatted = formatAmount(totalDebitAmount)
+	s.Trailer.NetAdjustmentAmount = totalCreditAmount + totalDebitAmount
+	s.Trailer.NetAdjustmentAmountFormatted = formatAmount(s.Trailer.NetAdjustmentAmount)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard812V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		errLineItem := enc.Encode(lineItem)
+		if errLineItem != nil {
+			return nil, errLineItem
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard812V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard812V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard812V1LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.LineItems = append(s.LineItems, x)
+		case "09":
+			var x Standard812V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+func (s *Standard812V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.MemoNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.MemoDate = req[5]
+	}
+	if len(req) > 6 {
+		s.CreditDebitFlag = req[6]
+	}
+	if len(req) > 7 {
+		s.InvoiceNumber = req[7]
+	}
+	if len(req) > 8 {
+		s.InvoiceDate = req[8]
+	}
+	if len(req) > 9 {
+		s.PurchaseOrderNumber = req[9]
+	}
+	if len(req) > 10 {
+		s.PODate = req[10]
+	}
+	if len(req) > 11 {
+		s.CurrencyCode = req[11]
+	}
+	if len(req) > 12 {
+		if req[12] != "" {
+			totalMemoAmount, err := parseAmount(req[12])
+			if err != nil {
+				return err
+			}
+			s.TotalMemoAmount = standard812V1Signed(s.CreditDebitFlag, totalMemoAmount)
+		}
+	}
+	if len(req) > 13 {
+		s.VendorID = req[13]
+	}
+	if len(req) > 14 {
+		s.PurchaserAccountID = req[14]
+	}
+
+	return nil
+}
+
+func (s *Standard812V1LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.PurchaseOrderLineItemNumber = req[2]
+	}
+	if len(req) > 3 {
+		s.ItemIdentificationGTIN = req[3]
+	}
+	if len(req) > 4 {
+		s.AdjustmentReasonCode = req[4]
+	}
+	if len(req) > 5 {
+		s.CreditDebitFlag = req[5]
+	}
+	if len(req) > 6 {
+		if req[6] != "" {
+			adjustmentAmount, err := parseAmount(req[6])
+			if err != nil {
+				return err
+			}
+			s.AdjustmentAmount = standard812V1Signed(s.CreditDebitFlag, adjustmentAmount)
+		}
+	}
+	if len(req) > 7 {
+		if req[7] != "" {
+			adjustmentQuantity, err := strconv.Atoi(req[7])
+			if err != nil {
+				return err
+			}
+			s.AdjustmentQuantity = adjustmentQuantity
+		}
+	}
+	if len(req) > 8 {
+		s.UnitOfMeasure = req[8]
+	}
+	if len(req) > 9 {
+		if req[9] != "" {
+			unitPrice, err := parseAmount(req[9])
+			if err != nil {
+				return err
+			}
+			s.UnitPrice = unitPrice
+		}
+	}
+	if len(req) > 10 {
+		s.AdjustmentDescription = req[10]
+	}
+
+	return nil
+}
+
+func (s *Standard812V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			totalCreditAmount, err := parseAmount(req[2])
+			if err != nil {
+				return err
+			}
+			s.TotalCreditAmount = standard812V1Signed(Standard812V1Credit, totalCreditAmount)
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			totalDebitAmount, err := parseAmount(req[3])
+			if err != nil {
+				return err
+			}
+			s.TotalDebitAmount = totalDebitAmount
+		}
+	}
+	if len(req) > 4 {
+		if req[4] != "" {
+			netAdjustmentAmount, err := parseAmount(req[4])
+			if err != nil {
+				return err
+			}
+			s.NetAdjustmentAmount = netAdjustmentAmount
+		}
+	}
+
+	return nil
+}
+
+func standard812V1Flag(amount int) string {
+
+	if amount < 0 {
+		return Standard812V1Credit
+	}
+
+	return Standard812V1Debit
+}
+
+// Some trading partners send credits as positive amounts and rely on the flag
+// alone, so a credit is always read back as a negative amount.
+func standard812V1Signed(flag string, amount int) int {
+
+	if flag == Standard812V1Credit && amount > 0 {
+		return -amount
+	}
+
+	return amount
+}
@@ -0,0 +1,128 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard820V1s = []Standard820V1{
+		Standard820V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard820V1Transaction{
+				PaymentNumber:      "ACH000123",
+				PaymentMethodCode:  "ACH",
+				CurrencyCode:       "USD",
+				PayerName:          "Overlook Hotel",
+				PurchaserAccountID: "12345",
+				VendorID:           "707738",
+			},
+			Remittances: []Standard820V1Remittance{
+				Standard820V1Remittance{
+					InvoiceNumber:       "INV-1",
+					PurchaseOrderNumber: "12345678",
+					InvoiceAmount:       3330,
+					AmountPaid:          3330,
+				},
+				Standard820V1Remittance{
+					InvoiceNumber:       "INV-2",
+					PurchaseOrderNumber: "12345679",
+					InvoiceAmount:       10000,
+					AmountPaid:          9250,
+					DiscountAmount:      200,
+					Adjustments: []Standard820V1Adjustment{
+						Standard820V1Adjustment{
+							AdjustmentReasonCode:  AdjustmentReasonItemNotAcceptedDamaged,
+							AdjustmentAmount:      -550,
+							AdjustmentDescription: "Damaged cartons",
+						},
+					},
+				},
+				Standard820V1Remittance{
+					InvoiceNumber: "INV-3",
+					InvoiceAmount: 5000,
+					AmountPaid:    4000,
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard820V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard820V1Key, Standard820V1 := range Standard820V1s {
+		byteArrayPointer, err := Standard820V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/820-"+strconv.Itoa(Standard820V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 16580, Standard820V1.Transaction.TotalPaymentAmount)
+		assert.Equal(t, -550, Standard820V1.Trailer.TotalAdjustmentAmount)
+	}
+
+}
+
+func TestStandard820V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/820-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard820V1 Standard820V1
+	err := standard820V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 16580, standard820V1.Transaction.TotalPaymentAmount)
+	if assert.Len(t, standard820V1.Remittances, 3) && assert.Len(t, standard820V1.Remittances[1].Adjustments, 1) {
+		assert.Equal(t, -550, standard820V1.Remittances[1].Adjustments[0].AdjustmentAmount)
+	}
+	assert.Equal(t, -550, standard820V1.Trailer.TotalAdjustmentAmount)
+
+}
+
+func TestStandard820V1Reconcile(t *testing.T) {
+
+	ctx := context.Background()
+
+	invoices := []Standard820V1Invoice{
+		Standard820V1Invoice{InvoiceNumber: "INV-1", PurchaseOrderNumber: "12345678", InvoiceAmount: 3330},
+		Standard820V1Invoice{InvoiceNumber: "INV-2", PurchaseOrderNumber: "12345679", InvoiceAmount: 10000},
+	}
+
+	reconciliations := Standard820V1s[0].Reconcile(ctx, invoices)
+	if assert.Len(t, reconciliations, 3) {
+		assert.Equal(t, Standard820V1StatusPaid, reconciliations[0].Status)
+		assert.Equal(t, Standard820V1StatusDeduction, reconciliations[1].Status)
+		assert.Equal(t, 550, reconciliations[1].Difference)
+		assert.Equal(t, Standard820V1StatusUnmatched, reconciliations[2].Status)
+	}
+
+	invoices = append(invoices, Standard820V1Invoice{InvoiceNumber: "INV-3", InvoiceAmount: 5000})
+	reconciliations = Standard820V1s[0].Reconcile(ctx, invoices)
+	if assert.Len(t, reconciliations, 3) {
+		assert.Equal(t, Standard820V1StatusShortPaid, reconciliations[2].Status)
+		assert.Equal(t, 1000, reconciliations[2].Difference)
+	}
+
+}
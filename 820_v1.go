@@ -0,0 +1,535 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard820V1StatusPaid      = "PAID"
+	Standard820V1StatusShortPaid = "SHORT"
+	Standard820V1StatusOverpaid  = "OVER"
+	Standard820V1StatusDeduction = "DEDUCTION"
+	Standard820V1StatusUnmatched = "UNMATCHED"
+)
+
+type Standard820V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard820V1Transaction
+	Remittances       []Standard820V1Remittance
+	Trailer           Standard820V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard820V1Transaction struct {
+	Header                      string
+	TransactionType             string
+	TransactionSetPurpose       string
+	VersionNumber               string
+	PaymentNumber               string
+	PaymentDate                 string
+	PaymentMethodCode           string
+	CurrencyCode                string
+	TotalPaymentAmount          int `csv:"-"`
+	TotalPaymentAmountFormatted string
+	PayerName                   string
+	PurchaserAccountID          string
+	PayeeName                   string
+	VendorID                    string
+}
+
+type Standard820V1Remittance struct {
+	DetailSectionLoopA      string
+	LineItemNumber          int
+	InvoiceNumber           string
+	InvoiceDate             string
+	PurchaseOrderNumber     string
+	InvoiceAmount           int `csv:"-"`
+	InvoiceAmountFormatted  string
+	AmountPaid              int `csv:"-"`
+	AmountPaidFormatted     string
+	DiscountAmount          int `csv:"-"`
+	DiscountAmountFormatted string
+	Adjustments             []Standard820V1Adjustment `csv:"-"`
+}
+
+type Standard820V1Adjustment struct {
+	DetailSectionLoopB        string
+	AdjustmentReasonCode      string
+	AdjustmentAmount          int `csv:"-"`
+	AdjustmentAmountFormatted string
+	AdjustmentDescription     string
+}
+
+type Standard820V1Trailer struct {
+	TrailerRecord                  string
+	RecordCount                    int
+	TotalAmountPaid                int `csv:"-"`
+	TotalAmountPaidFormatted       string
+	TotalAdjustmentAmount          int `csv:"-"`
+	TotalAdjustmentAmountFormatted string
+}
+
+type Standard820V1Invoice struct {
+	InvoiceNumber       string
+	PurchaseOrderNumber string
+	InvoiceAmount       int
+}
+
+type Standard820V1Reconciliation struct {
+	InvoiceNumber       string
+	PurchaseOrderNumber string
+	InvoiceAmount       int
+	AmountPaid          int
+	DiscountAmount      int
+	AdjustmentAmount    int
+	Difference          int
+	Status              string
+	Adjustments         []Standard820V1Adjustment
+}
+
+func (s *Standard820V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "820"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "820"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	if s.Transaction.PaymentDate == "" {
+		s.Transaction.PaymentDate = time.Now().Format("20060102")
+	}
+
+	// Remittances
+	var totalAmountPaid, totalAdjustmentAmount int
+	for remittanceKey, remittance := range s.Remittances {
+		s.Remittances[remittanceKey].DetailSectionLoopA = "02"
+		s.Remittances[remittanceKey].LineItemNumber = remittanceKey + 1
+		s.Remittances[remittanceKey].InvoiceAmountFormatted = formatAmount(remittance.InvoiceAmount)
+		s.Remittances[remittanceKey].AmountPaidFormatted = formatAmount(remittance.AmountPaid)
+		s.Remittances[remittanceKey].DiscountAmountFormatted = formatAmount(remittance.DiscountAmount)
+		totalAmountPaid += remittance.AmountPaid
+
+		// Adjustments
+		for adjustmentKey, adjustment := range remittance.Adjustments {
+			s.Remittances[remittanceKey].Adjustments[adjustmentKey].DetailSectionLoopB = "03"
+			s.Remittances[remittanceKey].Adjustments[adjustmentKey].AdjustmentAmountFormatted = formatAmount(adjustment.AdjustmentAmount)
+			totalAdjustmentAmount += adjustment.AdjustmentAmount
+		}
+	}
+	s.Transaction.TotalPaymentAmount = totalAmountPaid
+	s.Transaction.TotalPaymentAmountFormatted = formatAmount(totalAmountPaid)
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.Remittances)
+	s.Trailer.TotalAmountPaid = totalAmountPaid
+	s.Trailer.TotalAmountPaidFormatted = formatAmount(totalAmountPaid)
+	s.Trailer.TotalAdjustmentAmount = totalAdjustmentAmount
+	s.Trailer.TotalAdjustmentAmountFormatted = formatAmount(totalAdjustmentAmount)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard820V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Remittances
+	for _, remittance := range s.Remittances {
+		errRemittance := enc.Encode(remittance)
+		if errRemittance != nil {
+			return nil, errRemittance
+		}
+
+		// Adjustments
+		for _, adjustment := range remittance.Adjustments {
+			errAdjustment := enc.Encode(adjustment)
+			if errAdjustment != nil {
+				return nil, errAdjustment
+			}
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard820V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	var remittanceCount int
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard820V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard820V1Remittance
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Remittances = append(s.Remittances, x)
+			remittanceCount++
+		case "03":
+			var x Standard820V1Adjustment
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			if remittanceCount <= 0 {
+				s.Remittances = append(s.Remittances, Standard820V1Remittance{})
+				remittanceCount++
+			}
+			s.Remittances[remittanceCount-1].Adjustments = append(s.Remittances[remittanceCount-1].Adjustments, x)
+		case "09":
+			var x Standard820V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// Reconcile matches each remittance line to an issued invoice, by invoice
+// number or, when the remittance has none, by purchase order number. The
+// expected payment is the invoice amount less any discount taken; adjustments
+// that account for the remainder are reported as deductions.
+func (s *Standard820V1) Reconcile(ctx context.Context, invoices []Standard820V1Invoice) []Standard820V1Reconciliation {
+
+	var reconciliations []Standard820V1Reconciliation
+	for _, remittance := range s.Remittances {
+
+		reconciliation := Standard820V1Reconciliation{
+			InvoiceNumber:       remittance.InvoiceNumber,
+			PurchaseOrderNumber: remittance.PurchaseOrderNumber,
+			AmountPaid:          remittance.AmountPaid,
+			DiscountAmount:      remittance.DiscountAmount,
+			Adjustments:         remittance.Adjustments,
+		}
+		for _, adjustment := range remittance.Adjustments {
+			reconciliation.AdjustmentAmount += adjustment.AdjustmentAmount
+		}
+
+		// Invoice
+		invoiceKey := -1
+		for key, invoice := range invoices {
+			if remittance.InvoiceNumber != "" && invoice.InvoiceNumber == remittance.InvoiceNumber {
+				invoiceKey = key
+				break
+			}
+			if remittance.InvoiceNumber == "" && remittance.PurchaseOrderNumber != "" && invoice.PurchaseOrderNumber == remittance.PurchaseOrderNumber {
+				invoiceKey = key
+				break
+			}
+		}
+		if invoiceKey < 0 {
+			reconciliation.Status = Standard820V1StatusUnmatched
+			reconciliations = append(reconciliations, reconciliation)
+			continue
+		}
+		reconciliation.InvoiceNumber = invoices[invoiceKey].InvoiceNumber
+		reconciliation.PurchaseOrderNumber = invoices[invoiceKey].PurchaseOrderNumber
+		reconciliation.InvoiceAmount = invoices[invoiceKey].InvoiceAmount
+
+		// Status
+		reconciliation.Difference = reconciliation.InvoiceAmount - reconciliation.DiscountAmount - reconciliation.AmountPaid
+		switch {
+		case reconciliation.Difference == 0:
+			reconciliation.Status = Standard820V1StatusPaid
+		case reconciliation.Difference > 0 && reconciliation.Difference == -reconciliation.AdjustmentAmount:
+			reconciliation.Status = Standard820V1StatusDeduction
+		case reconciliation.Difference > 0:
+			reconciliation.Status = Standard820V1StatusShortPaid
+		default:
+			reconciliation.Status = Standard820V1StatusOverpaid
+		}
+
+		reconciliations = append(reconciliations, reconciliation)
+	}
+
+	return reconciliations
+}
+
+func (s *Standard820V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.PaymentNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.PaymentDate = req[5]
+	}
+	if len(req) > 6 {
+		s.PaymentMethodCode = req[6]
+	}
+	if len(req) > 7 {
+		s.CurrencyCode = req[7]
+	}
+	if len(req) > 8 {
+		if req[8] != "" {
+			totalPaymentAmount, err := parseAmount(req[8])
+			if err != nil {
+				return err
+			}
+			s.TotalPaymentAmount = totalPaymentAmount
+		}
+	}
+	if len(req) > 9 {
+		s.PayerName = req[9]
+	}
+	if len(req) > 10 {
+		s.PurchaserAccountID = req[10]
+	}
+	if len(req) > 11 {
+		s.PayeeName = req[11]
+	}
+	if len(req) > 12 {
+		s.VendorID = req[12]
+	}
+
+	return nil
+}
+
+func (s *Standard820V1Remittance) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.InvoiceNumber = req[2]
+	}
+	if len(req) > 3 {
+		s.InvoiceDate = req[3]
+	}
+	if len(req) > 4 {
+		s.PurchaseOrderNumber = req[4]
+	}
+	if len(req) > 5 {
+		if req[5] != "" {
+			invoiceAmount, err := parseAmount(req[5])
+			if err != nil {
+				return err
+			}
+			s.InvoiceAmount = invoiceAmount
+		}
+	}
+	if len(req) > 6 {
+		if req[6] != "" {
+			amountPaid, err := parseAmount(req[6])
+			if err != nil {
+				return err
+			}
+			s.AmountPaid = amountPaid
+		}
+	}
+	if len(req) > 7 {
+		if req[7] != "" {
+			discountAmount, err := parseAmount(req[7])
+			if err != nil {
+				return err
+			}
+			s.DiscountAmount = discountAmount
+		}
+	}
+
+	return nil
+}
+
+func (s *Standard820V1Adjustment) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopB = req[0]
+	}
+	if len(req) > 1 {
+		s.AdjustmentReasonCode = req[1]
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			adjustmentAmount, err := parseAmount(req[2])
+			if err != nil {
+				return err
+			}
+			s.AdjustmentAmount = adjustmentAmount
+		}
+	}
+	if len(req) > 3 {
+		s.AdjustmentDescription = req[3]
+	}
+
+	return nil
+}
+
+func (s *Standard820V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			totalAmountPaid, err := parseAmount(req[2])
+			if err != nil {
+				return err
+			}
+			s.TotalAmountPaid = totalAmountPaid
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			totalAdjustmentAmount, err := parseAmount(req[3])
+			if err != nil {
+				return err
+			}
+			s.TotalAdjustmentAmount = totalAdjustmentAmount
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandard824V1FromValidationReport(t *testing.T) {
+
+	ctx := context.Background()
+
+	report := ValidationReport{
+		InterchangeID:       "202102268484912",
+		TransactionType:     "850",
+		PurchaseOrderNumber: "12345678",
+		Errors: []ValidationError{
+			ValidationError{
+				RecordType:     "02",
+				LineItemNumber: 2,
+				Field:          "ItemIdentificationGTIN",
+				Code:           ValidationCodeUnknownGTIN,
+				Message:        "GTIN 00821780002799 is not in the catalog",
+			},
+			ValidationError{
+				RecordType: "01",
+				Field:      "StoreID",
+				Code:       ValidationCodeClosedStore,
+				Message:    "Store 8976 is closed",
+			},
+		},
+	}
+
+	standard824V1 := Standard824V1{
+		EnvelopeHeaderV3: EnvelopeHeaderV3{
+			InterchangeID: "202102268484999",
+			ReceiverID:    "383601069",
+			SenderID:      "123456789",
+		},
+		EnvelopeTrailerV3: EnvelopeTrailerV3{
+			InterchangeID: "202102268484999",
+		},
+	}
+	err := standard824V1.FromValidationReport(ctx, report)
+	assert.Nil(t, err)
+
+	byteArrayPointer, err := standard824V1.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer != nil {
+		err := ioutil.WriteFile("./examples/824-0.txt", *byteArrayPointer, 0644)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, Standard824V1Rejected, standard824V1.Transaction.ApplicationAcknowledgementCode)
+	assert.Equal(t, 2, standard824V1.Trailer.RecordCount)
+
+}
+
+func TestStandard824V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/824-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard824V1 Standard824V1
+	err := standard824V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "202102268484912", standard824V1.Transaction.OriginalInterchangeID)
+	if assert.Len(t, standard824V1.Errors, 2) {
+		assert.Equal(t, "12345678", standard824V1.Errors[0].PurchaseOrderNumber)
+		assert.Equal(t, 2, standard824V1.Errors[0].RecordLineItemNumber)
+		assert.Equal(t, ValidationCodeUnknownGTIN, standard824V1.Errors[0].ErrorCode)
+		assert.Equal(t, ValidationCodeClosedStore, standard824V1.Errors[1].ErrorCode)
+	}
+
+}
@@ -0,0 +1,383 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard824V1Accepted           = "TA"
+	Standard824V1AcceptedWithErrors = "TE"
+	Standard824V1Rejected           = "TR"
+)
+
+// Standard824V1AcknowledgementCodes lists the application acknowledgement
+// codes.
+var Standard824V1AcknowledgementCodes = []string{Standard824V1Accepted, Standard824V1AcceptedWithErrors, Standard824V1Rejected}
+
+type Standard824V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard824V1Transaction
+	Errors            []Standard824V1Error
+	Trailer           Standard824V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard824V1Transaction struct {
+	Header                         string
+	TransactionType                string
+	TransactionSetPurpose          string
+	VersionNumber                  string
+	ReferenceNumber                string
+	AdviceDate                     string
+	AdviceTime                     string
+	VendorID                       string
+	PurchaserAccountID             string
+	OriginalInterchangeID          string
+	OriginalTransactionType        string
+	ApplicationAcknowledgementCode string
+}
+
+type Standard824V1Error struct {
+	DetailSectionLoopA    string
+	LineItemNumber        int
+	OriginalInterchangeID string
+	PurchaseOrderNumber   string
+	RecordType            string
+	RecordLineItemNumber  int
+	FieldName             string
+	ErrorCode             string
+	ErrorMessage          string
+}
+
+type Standard824V1Trailer struct {
+	TrailerRecord string
+	RecordCount   int
+}
+
+func (s *Standard824V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "824"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "824"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	s.Transaction.AdviceDate = time.Now().Format("20060102")
+	s.Transaction.AdviceTime = time.Now().Format("150405")
+	if s.Transaction.ApplicationAcknowledgementCode == "" {
+		s.Transaction.ApplicationAcknowledgementCode = Standard824V1Accepted
+		if len(s.Errors) > 0 {
+			s.Transaction.ApplicationAcknowledgementCode = Standard824V1Rejected
+		}
+	}
+
+	// Errors
+	for errorKey, errorRecord := range s.Errors {
+		s.Errors[errorKey].DetailSectionLoopA = "02"
+		s.Errors[errorKey].LineItemNumber = errorKey + 1
+		if errorRecord.OriginalInterchangeID == "" {
+			s.Errors[errorKey].OriginalInterchangeID = s.Transaction.OriginalInterchangeID
+		}
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.Errors)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard824V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Errors
+	for _, errorRecord := range s.Errors {
+		errError := enc.Encode(errorRecord)
+		if errError != nil {
+			return nil, errError
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard824V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard824V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard824V1Error
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Errors = append(s.Errors, x)
+		case "09":
+			var x Standard824V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// FromValidationReport fills the advice from a validation report of an
+// inbound document. A report without errors is accepted, any error rejects it.
+func (s *Standard824V1) FromValidationReport(ctx context.Context, report ValidationReport) error {
+
+	s.Transaction.OriginalInterchangeID = report.InterchangeID
+	s.Transaction.OriginalTransactionType = report.TransactionType
+	s.Transaction.ApplicationAcknowledgementCode = Standard824V1Accepted
+	if !report.Valid() {
+		s.Transaction.ApplicationAcknowledgementCode = Standard824V1Rejected
+	}
+
+	// Errors
+	s.Errors = nil
+	for _, validationError := range report.Errors {
+		purchaseOrderNumber := validationError.PurchaseOrderNumber
+		if purchaseOrderNumber == "" {
+			purchaseOrderNumber = report.PurchaseOrderNumber
+		}
+		s.Errors = append(s.Errors, Standard824V1Error{
+			OriginalInterchangeID: report.InterchangeID,
+			PurchaseOrderNumber:   purchaseOrderNumber,
+			RecordType:            validationError.RecordType,
+			RecordLineItemNumber:  validationError.LineItemNumber,
+			FieldName:             validationError.Field,
+			ErrorCode:             validationError.Code,
+			ErrorMessage:          validationError.Message,
+		})
+	}
+
+	return nil
+}
+
+func (s *Standard824V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.ReferenceNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.AdviceDate = req[5]
+	}
+	if len(req) > 6 {
+		s.AdviceTime = req[6]
+	}
+	if len(req) > 7 {
+		s.VendorID = req[7]
+	}
+	if len(req) > 8 {
+		s.PurchaserAccountID = req[8]
+	}
+	if len(req) > 9 {
+		s.OriginalInterchangeID = req[9]
+	}
+	if len(req) > 10 {
+		s.OriginalTransactionType = req[10]
+	}
+	if len(req) > 11 {
+		s.ApplicationAcknowledgementCode = req[11]
+	}
+
+	return nil
+}
+
+func (s *Standard824V1Error) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.OriginalInterchangeID = req[2]
+	}
+	if len(req) > 3 {
+		s.PurchaseOrderNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.RecordType = req[4]
+	}
+	if len(req) > 5 {
+		if req[5] != "" {
+			recordLineItemNumber, err := strconv.Atoi(req[5])
+			if err != nil {
+				return err
+			}
+			s.RecordLineItemNumber = recordLineItemNumber
+		}
+	}
+	if len(req) > 6 {
+		s.FieldName = req[6]
+	}
+	if len(req) > 7 {
+		s.ErrorCode = req[7]
+	}
+	if len(req) > 8 {
+		s.ErrorMessage = req[8]
+	}
+
+	return nil
+}
+
+func (s *Standard824V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+
+	return nil
+}
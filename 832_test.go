@@ -0,0 +1,102 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard832V1s = []Standard832V1{
+		Standard832V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Header: Standard832V1TransactionHeader{
+				CatalogNumber:      "SS21",
+				EffectiveDate:      "20210301",
+				VendorID:           "707738",
+				PurchaserAccountID: "12345",
+				CurrencyCode:       "USD",
+			},
+			LineItems: []Standard832V1LineItem{
+				Standard832V1LineItem{
+					ItemIdentificationGTIN:  "00821780002660",
+					MasterStyle:             "2002",
+					ColorCode:               "NAV",
+					SizeCode:                "S",
+					ItemDescription:         "Pocket Tee",
+					ColorDescription:        "Navy",
+					SizeDescription:         "Small",
+					PurchaseUnitPriceEaches: "1.8500",
+					PurchaseUnitPriceDozens: "21.0000",
+					PurchaseUnitPriceCases:  "60.0000",
+				},
+				Standard832V1LineItem{
+					ItemIdentificationGTIN:    "00821780002799",
+					MasterStyle:               "2002",
+					ColorCode:                 "NAV",
+					SizeCode:                  "M",
+					ItemDescription:           "Pocket Tee",
+					ColorDescription:          "Navy",
+					SizeDescription:           "Medium",
+					PurchaseUnitPriceEaches:   "1.8500",
+					CustomPriceUOMDescription: "Pack of 6",
+					PurchaseUnitPriceCustom:   "10.5000",
+					SuggestedRetailPrice:      "9.9900",
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard832V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard832V1Key, Standard832V1 := range Standard832V1s {
+		byteArrayPointer, err := Standard832V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/832-"+strconv.Itoa(Standard832V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, Standard832V1.Trailer.RecordCount)
+	}
+
+}
+
+func TestStandard832V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/832-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard832V1 Standard832V1
+	err := standard832V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "SS21", standard832V1.Header.CatalogNumber)
+	if assert.Len(t, standard832V1.LineItems, 2) {
+		assert.Equal(t, "60.0000", standard832V1.LineItems[0].PurchaseUnitPriceCases)
+		assert.Equal(t, "Pack of 6", standard832V1.LineItems[1].CustomPriceUOMDescription)
+		assert.Equal(t, "9.9900", standard832V1.LineItems[1].SuggestedRetailPrice)
+	}
+	assert.Equal(t, 2, standard832V1.Trailer.RecordCount)
+
+}
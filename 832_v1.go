@@ -0,0 +1,364 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+type Standard832V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Header            Standard832V1TransactionHeader
+	LineItems         []Standard832V1LineItem
+	Trailer           Standard832V1TransactionTrailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard832V1TransactionHeader struct {
+	Header                string
+	TransactionType       string
+	TransactionSetPurpose string
+	VersionNumber         string
+	CatalogNumber         string
+	CatalogDate           string
+	EffectiveDate         string
+	ExpirationDate        string
+	VendorID              string
+	PurchaserAccountID    string
+	CurrencyCode          string
+}
+
+type Standard832V1TransactionTrailer struct {
+	TrailerRecord    string
+	FileCreationDate string
+	FileCreationTime string
+	RecordCount      int
+}
+
+type Standard832V1LineItem struct {
+	DetailSectionLoopA        string
+	LineItemNumber            int
+	ItemIdentificationGTIN    string
+	MasterStyle               string
+	ColorCode                 string
+	SizeCode                  string
+	ItemDescription           string
+	ColorDescription          string
+	SizeDescription           string
+	UnitOfMeasure             string
+	PurchaseUnitPriceEaches   string
+	PurchaseUnitPriceDozens   string
+	PurchaseUnitPriceCases    string
+	CustomPriceUOMDescription string
+	PurchaseUnitPriceCustom   string
+	SuggestedRetailPrice      string
+}
+
+func (s *Standard832V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "832"
+
+	// Transaction
+	s.Header.Header = "01"
+	s.Header.TransactionType = "832"
+	if s.Header.TransactionSetPurpose == "" {
+		s.Header.TransactionSetPurpose = "00"
+	}
+	s.Header.VersionNumber = "1.0"
+	if s.Header.CatalogDate == "" {
+		s.Header.CatalogDate = time.Now().Format("20060102")
+	}
+
+	// Line Items
+	for lineItemKey, lineItem := range s.LineItems {
+		s.LineItems[lineItemKey].DetailSectionLoopA = "02"
+		s.LineItems[lineItemKey].LineItemNumber = lineItemKey + 1
+		if lineItem.UnitOfMeasure == "" {
+			s.LineItems[lineItemKey].UnitOfMeasure = "EA"
+		}
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.FileCreationDate = time.Now().Format("20060102")
+	s.Trailer.FileCreationTime = time.Now().Format("150405")
+	s.Trailer.RecordCount = len(s.LineItems)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard832V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Header
+	errTransaction := enc.Encode(s.Header)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		errLineItem := enc.Encode(lineItem)
+		if errLineItem != nil {
+			return nil, errLineItem
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard832V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard832V1TransactionHeader
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Header = x
+		case "02":
+			var x Standard832V1LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.LineItems = append(s.LineItems, x)
+		case "09":
+			var x Standard832V1TransactionTrailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+func (s *Standard832V1TransactionHeader) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.CatalogNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.CatalogDate = req[5]
+	}
+	if len(req) > 6 {
+		s.EffectiveDate = req[6]
+	}
+	if len(req) > 7 {
+		s.ExpirationDate = req[7]
+	}
+	if len(req) > 8 {
+		s.VendorID = req[8]
+	}
+	if len(req) > 9 {
+		s.PurchaserAccountID = req[9]
+	}
+	if len(req) > 10 {
+		s.CurrencyCode = req[10]
+	}
+
+	return nil
+}
+
+func (s *Standard832V1TransactionTrailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		s.FileCreationDate = req[1]
+	}
+	if len(req) > 2 {
+		s.FileCreationTime = req[2]
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			recordCount, err := strconv.Atoi(req[3])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+
+	return nil
+}
+
+func (s *Standard832V1LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.ItemIdentificationGTIN = req[2]
+	}
+	if len(req) > 3 {
+		s.MasterStyle = req[3]
+	}
+	if len(req) > 4 {
+		s.ColorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.SizeCode = req[5]
+	}
+	if len(req) > 6 {
+		s.ItemDescription = req[6]
+	}
+	if len(req) > 7 {
+		s.ColorDescription = req[7]
+	}
+	if len(req) > 8 {
+		s.SizeDescription = req[8]
+	}
+	if len(req) > 9 {
+		s.UnitOfMeasure = req[9]
+	}
+	if len(req) > 10 {
+		s.PurchaseUnitPriceEaches = req[10]
+	}
+	if len(req) > 11 {
+		s.PurchaseUnitPriceDozens = req[11]
+	}
+	if len(req) > 12 {
+		s.PurchaseUnitPriceCases = req[12]
+	}
+	if len(req) > 13 {
+		s.CustomPriceUOMDescription = req[13]
+	}
+	if len(req) > 14 {
+		s.PurchaseUnitPriceCustom = req[14]
+	}
+	if len(req) > 15 {
+		s.SuggestedRetailPrice = req[15]
+	}
+
+	return nil
+}
@@ -2,14 +2,163 @@ package easi
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	Standard846V3s = []Standard846V3{
+		Standard846V3{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Sections: []Standard846V3Section{
+				Standard846V3Section{
+					Header: Standard846V3TransactionHeader{
+						VendorID:             "707738",
+						AsOfDate:             "20210226",
+						AsOfTime:             "060000",
+						DistributionCenter:   "CHARLOTTE",
+						DistributionCenterID: "CLT1",
+					},
+					LineItems: []Standard846V3LineItem{
+						Standard846V3LineItem{ItemIdentificationGTIN: "00821780002660", CurrentInventoryLevel: 118, QuantityToArriveWithinTheNextTwoWeeks: "48", PurchaseUnitPriceEaches: "1.8500"},
+						Standard846V3LineItem{ItemIdentificationGTIN: "00821780002799", CurrentInventoryLevel: 0, QuantityToArriveWithinTheNextTwoWeeks: "96", PurchaseUnitPriceEaches: "1.8500"},
+					},
+				},
+				Standard846V3Section{
+					Header: Standard846V3TransactionHeader{
+						VendorID:             "707738",
+						DistributionCenter:   "RENO",
+						DistributionCenterID: "RNO1",
+					},
+					LineItems: []Standard846V3LineItem{
+						Standard846V3LineItem{ItemIdentificationGTIN: "00821780002660", CurrentInventoryLevel: 12, PurchaseUnitPriceEaches: "1.8500"},
+					},
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+		Standard846V3{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484913",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Header: Standard846V3TransactionHeader{
+				VendorID:             "707738",
+				DistributionCenterID: "CLT1",
+			},
+			LineItems: []Standard846V3LineItem{
+				Standard846V3LineItem{ItemIdentificationGTIN: "00707738003265", CurrentInventoryLevel: 64},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484913",
+			},
+		},
+	}
+)
+
+func TestStandard846V3ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard846V3Key, Standard846V3 := range Standard846V3s {
+		byteArrayPointer, err := Standard846V3.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/846-"+strconv.Itoa(Standard846V3Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		for _, section := range Standard846V3.Sections {
+			assert.Equal(t, len(section.LineItems), section.Trailer.RecordCount)
+			assert.NotEmpty(t, section.Header.AsOfDate)
+			assert.NotEmpty(t, section.Header.AsOfTime)
+		}
+	}
+
+}
+
+func TestStandard846V3RoundTrip(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, expected := range Standard846V3s {
+		byteArrayPointer, err := expected.ToBytes(ctx)
+		assert.Nil(t, err)
+
+		var standard846V3 Standard846V3
+		err = standard846V3.FromBytes(ctx, *byteArrayPointer)
+		assert.Nil(t, err)
+
+		assert.Equal(t, expected.Sections, standard846V3.Sections)
+		assert.Equal(t, expected.LineItems, standard846V3.LineItems)
+		assert.Equal(t, expected.Trailer, standard846V3.Trailer)
+	}
+
+	assert.Equal(t, "20210226", Standard846V3s[0].Sections[0].Header.AsOfDate)
+	assert.Equal(t, "RNO1", Standard846V3s[0].Sections[1].Header.DistributionCenterID)
+
+	// Example
+	bytes, readErr := ioutil.ReadFile("./examples/846.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard846V3 Standard846V3
+	err := standard846V3.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+	byteArrayPointer, err := standard846V3.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+
+	r := csv.NewReader(strings.NewReader(string(bytes)))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+	expectedRecords, err := r.ReadAll()
+	assert.Nil(t, err)
+	r = csv.NewReader(strings.NewReader(string(*byteArrayPointer)))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	assert.Nil(t, err)
+	if assert.Len(t, records, len(expectedRecords)) {
+		for recordKey, expectedRecord := range expectedRecords {
+			record := records[recordKey]
+
+			// File creation date and time are written by Prep
+			switch expectedRecord[0] {
+			case "EASI":
+				expectedRecord = append(expectedRecord[:6:6], expectedRecord[8:]...)
+				record = append(record[:6:6], record[8:]...)
+			case "09":
+				expectedRecord = append(expectedRecord[:1:1], expectedRecord[3:]...)
+				record = append(record[:1:1], record[3:]...)
+			}
+			assert.Equal(t, expectedRecord, record)
+		}
+	}
+
+}
+
 func TestStandard846V3FromBytes(t *testing.T) {
 
 	ctx := context.Background()
@@ -23,7 +172,69 @@ func TestStandard846V3FromBytes(t *testing.T) {
 	err := standard846V3.FromBytes(ctx, bytes)
 	assert.Nil(t, err)
 
+	if assert.Len(t, standard846V3.Sections, 2) && assert.Len(t, standard846V3.Sections[0].LineItems, 3) {
+		assert.Equal(t, "RNO1", standard846V3.Sections[1].Header.DistributionCenterID)
+		assert.Equal(t, "INNER PACK 6", standard846V3.Sections[0].LineItems[2].CustomPriceUOMDescription)
+		assert.Equal(t, "25.5000", standard846V3.Sections[0].LineItems[2].PurchaseUnitPriceCustom)
+	}
+
 	c, _ := json.Marshal(standard846V3)
 	fmt.Println(string(c))
 
 }
+
+func TestStandard846V3Index(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/846.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard846V3 Standard846V3
+	err := standard846V3.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	index, err := standard846V3.Index(ctx)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"CLT1", "RNO1"}, index.DistributionCenterIDs)
+
+		inventory, ok := index.Lookup(ctx, "00821780002660", "CLT1")
+		assert.True(t, ok)
+		assert.Equal(t, 118, inventory.CurrentInventoryLevel)
+		assert.Equal(t, 48, inventory.InboundQuantity)
+
+		_, ok = index.Lookup(ctx, "00821780002799", "RNO1")
+		assert.False(t, ok)
+
+		total, ok := index.Total(ctx, "00707738003265")
+		assert.True(t, ok)
+		assert.Equal(t, Standard846V3Inventory{ItemIdentificationGTIN: "00707738003265", CurrentInventoryLevel: 314, InboundQuantity: 120}, total)
+
+		asOf, ok := index.AsOf(ctx, "RNO1")
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2021, 3, 1, 6, 0, 0, 0, time.UTC), asOf)
+	}
+
+	// Duplicate Distribution Center
+	standard846V3.Sections[1].Header.DistributionCenterID = "CLT1"
+	standard846V3.Sections[1].Header.AsOfDate = ""
+	index, err = standard846V3.Index(ctx)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"CLT1"}, index.DistributionCenterIDs)
+
+		inventory, ok := index.Lookup(ctx, "00821780002660", "CLT1")
+		assert.True(t, ok)
+		assert.Equal(t, 130, inventory.CurrentInventoryLevel)
+
+		asOf, ok := index.AsOf(ctx, "CLT1")
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2021, 3, 1, 6, 0, 0, 0, time.UTC), asOf)
+	}
+
+	standard846V3.Sections[0].LineItems[0].QuantityToArriveWithinTheNextTwoWeeks = "TBD"
+	_, err = standard846V3.Index(ctx)
+	assert.NotNil(t, err)
+
+}
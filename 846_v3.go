@@ -5,8 +5,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"fmt"
 	"io"
 	"strconv"
+	"time"
 
 	"github.com/jszwec/csvutil"
 )
@@ -61,20 +63,154 @@ type Standard846V3LineItem struct {
 	PurchaseUnitPriceCustom               string
 }
 
+type Standard846V3Index struct {
+	DistributionCenterIDs []string
+	asOf                  map[string]time.Time
+	inventory             map[string]map[string]Standard846V3Inventory
+}
+
+type Standard846V3Inventory struct {
+	ItemIdentificationGTIN string
+	DistributionCenterID   string
+	CurrentInventoryLevel  int
+	InboundQuantity        int
+}
+
+// Prep writes one section per distribution center. When no Sections are set
+// the flat Header, LineItems and Trailer make up a single section, and once
+// prepared the flat fields mirror what FromBytes would produce.
 func (s *Standard846V3) Prep(ctx context.Context) error {
 
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "846"
+
+	// Sections
+	if len(s.Sections) == 0 {
+		s.Sections = append(s.Sections, Standard846V3Section{
+			Header:    s.Header,
+			LineItems: s.LineItems,
+			Trailer:   s.Trailer,
+		})
+	}
+
+	s.LineItems = nil
+	for sectionKey, section := range s.Sections {
+
+		// Transaction
+		s.Sections[sectionKey].Header.Header = "01"
+		s.Sections[sectionKey].Header.TransactionType = "846"
+		if section.Header.TransactionSetPurpose == "" {
+			s.Sections[sectionKey].Header.TransactionSetPurpose = "00"
+		}
+		s.Sections[sectionKey].Header.VersionNumber = "3.0"
+		if section.Header.AsOfDate == "" {
+			s.Sections[sectionKey].Header.AsOfDate = time.Now().Format("20060102")
+		}
+		if section.Header.AsOfTime == "" {
+			s.Sections[sectionKey].Header.AsOfTime = time.Now().Format("150405")
+		}
+		if section.Header.TimeZone == "" {
+			s.Sections[sectionKey].Header.TimeZone = "UTC"
+		}
+
+		// Line Items
+		for lineItemKey, lineItem := range section.LineItems {
+			s.Sections[sectionKey].LineItems[lineItemKey].DetailSectionLoopA = "02"
+			s.Sections[sectionKey].LineItems[lineItemKey].LineItemNumber = lineItemKey + 1
+			if lineItem.UnitOfMeasure == "" {
+				s.Sections[sectionKey].LineItems[lineItemKey].UnitOfMeasure = "EA"
+			}
+		}
+
+		// Trailer
+		s.Sections[sectionKey].Trailer.TrailerRecord = "09"
+		s.Sections[sectionKey].Trailer.FileCreationDate = time.Now().Format("20060102")
+		s.Sections[sectionKey].Trailer.FileCreationTime = time.Now().Format("150405")
+		s.Sections[sectionKey].Trailer.RecordCount = len(section.LineItems)
+
+		s.Header = s.Sections[sectionKey].Header
+		s.LineItems = append(s.LineItems, s.Sections[sectionKey].LineItems...)
+		s.Trailer = s.Sections[sectionKey].Trailer
+	}
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
 	return nil
 }
 
 func (s *Standard846V3) ToBytes(ctx context.Context) (*[]byte, error) {
 
-	return nil, nil
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Sections
+	for _, section := range s.Sections {
+
+		// Transaction
+		errHeader := enc.Encode(section.Header)
+		if errHeader != nil {
+			return nil, errHeader
+		}
+
+		// Line Items
+		for _, lineItem := range section.LineItems {
+			errLineItem := enc.Encode(lineItem)
+			if errLineItem != nil {
+				return nil, errLineItem
+			}
+		}
+
+		// Trailer
+		errTrailer := enc.Encode(section.Trailer)
+		if errTrailer != nil {
+			return nil, errTrailer
+		}
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
 }
 
 func (s *Standard846V3) FromBytes(ctx context.Context, req []byte) error {
 
 	r := csv.NewReader(bytes.NewReader(req))
 	r.Comma = '\t'
+	r.FieldsPerRecord = -1
 
 	// Headerless
 	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
@@ -93,9 +229,13 @@ func (s *Standard846V3) FromBytes(ctx context.Context, req []byte) error {
 
 	for {
 		var v struct{}
-		if err := dec.Decode(&v); err == io.EOF {
+		err := dec.Decode(&v)
+		if err == io.EOF {
 			break
 		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
 
 		// Record
 		var lineType string
@@ -160,6 +300,128 @@ func (s *Standard846V3) FromBytes(ctx context.Context, req []byte) error {
 	return nil
 }
 
+// Index builds a lookup of inventory by GTIN and distribution center from the
+// Sections, or from the flat fields when there are none. A distribution center
+// reported in more than one section has its sections added together and keeps
+// the latest as-of time.
+func (s *Standard846V3) Index(ctx context.Context) (*Standard846V3Index, error) {
+
+	sections := s.Sections
+	if len(sections) == 0 {
+		sections = []Standard846V3Section{
+			Standard846V3Section{
+				Header:    s.Header,
+				LineItems: s.LineItems,
+				Trailer:   s.Trailer,
+			},
+		}
+	}
+
+	index := Standard846V3Index{
+		asOf:      map[string]time.Time{},
+		inventory: map[string]map[string]Standard846V3Inventory{},
+	}
+	for _, section := range sections {
+		distributionCenterID := section.Header.DistributionCenterID
+
+		// As Of
+		asOf, err := section.Header.AsOf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		latest, ok := index.asOf[distributionCenterID]
+		if !ok {
+			index.DistributionCenterIDs = append(index.DistributionCenterIDs, distributionCenterID)
+			index.inventory[distributionCenterID] = map[string]Standard846V3Inventory{}
+		}
+		if !ok || asOf.After(latest) {
+			index.asOf[distributionCenterID] = asOf
+		}
+
+		// Line Items
+		inventory := index.inventory[distributionCenterID]
+		for _, lineItem := range section.LineItems {
+			var inboundQuantity int
+			if lineItem.QuantityToArriveWithinTheNextTwoWeeks != "" {
+				inboundQuantity, err = strconv.Atoi(lineItem.QuantityToArriveWithinTheNextTwoWeeks)
+				if err != nil {
+					return nil, fmt.Errorf("846 inbound quantity for GTIN %s: %w", lineItem.ItemIdentificationGTIN, err)
+				}
+			}
+			item := inventory[lineItem.ItemIdentificationGTIN]
+			item.ItemIdentificationGTIN = lineItem.ItemIdentificationGTIN
+			item.DistributionCenterID = distributionCenterID
+			item.CurrentInventoryLevel += lineItem.CurrentInventoryLevel
+			item.InboundQuantity += inboundQuantity
+			inventory[lineItem.ItemIdentificationGTIN] = item
+		}
+	}
+
+	return &index, nil
+}
+
+// Lookup returns the inventory of a GTIN at one distribution center.
+func (s *Standard846V3Index) Lookup(ctx context.Context, gtin string, distributionCenterID string) (Standard846V3Inventory, bool) {
+
+	inventory, ok := s.inventory[distributionCenterID][gtin]
+
+	return inventory, ok
+}
+
+// Total returns the inventory of a GTIN summed across all distribution
+// centers, leaving DistributionCenterID blank.
+func (s *Standard846V3Index) Total(ctx context.Context, gtin string) (Standard846V3Inventory, bool) {
+
+	var found bool
+	total := Standard846V3Inventory{
+		ItemIdentificationGTIN: gtin,
+	}
+	for _, distributionCenterID := range s.DistributionCenterIDs {
+		inventory, ok := s.inventory[distributionCenterID][gtin]
+		if !ok {
+			continue
+		}
+		found = true
+		total.CurrentInventoryLevel += inventory.CurrentInventoryLevel
+		total.InboundQuantity += inventory.InboundQuantity
+	}
+
+	return total, found
+}
+
+// AsOf returns when the inventory of a distribution center was counted.
+func (s *Standard846V3Index) AsOf(ctx context.Context, distributionCenterID string) (time.Time, bool) {
+
+	asOf, ok := s.asOf[distributionCenterID]
+
+	return asOf, ok
+}
+
+// AsOf combines AsOfDate, AsOfTime and TimeZone. The time may be given with or
+// without seconds and an unknown or blank time zone is read as UTC. A blank
+// AsOfDate gives the zero time.
+func (s *Standard846V3TransactionHeader) AsOf(ctx context.Context) (time.Time, error) {
+
+	if s.AsOfDate == "" {
+		return time.Time{}, nil
+	}
+
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	layout := "20060102150405"
+	if len(s.AsOfTime) == 4 {
+		layout = "200601021504"
+	}
+	if s.AsOfTime == "" {
+		layout = "20060102"
+	}
+
+	return time.ParseInLocation(layout, s.AsOfDate+s.AsOfTime, location)
+}
+
 func (s *Standard846V3TransactionHeader) FromSlice(ctx context.Context, req []string) error {
 
 	if len(req) > 0 {
@@ -266,5 +528,33 @@ func (s *Standard846V3LineItem) FromSlice(ctx context.Context, req []string) err
 		}
 	}
 
+	if len(req) > 4 {
+		s.UnitOfMeasure = req[4]
+	}
+
+	if len(req) > 5 {
+		s.QuantityToArriveWithinTheNextTwoWeeks = req[5]
+	}
+
+	if len(req) > 6 {
+		s.PurchaseUnitPriceEaches = req[6]
+	}
+
+	if len(req) > 7 {
+		s.PurchaseUnitPriceDozens = req[7]
+	}
+
+	if len(req) > 8 {
+		s.PurchaseUnitPriceCases = req[8]
+	}
+
+	if len(req) > 9 {
+		s.CustomPriceUOMDescription = req[9]
+	}
+
+	if len(req) > 10 {
+		s.PurchaseUnitPriceCustom = req[10]
+	}
+
 	return nil
 }
@@ -160,4 +160,62 @@ func TestStandard850V4FromBytes(t *testing.T) {
 	err := Standard850V4.FromBytes(ctx, bytes)
 	assert.Nil(t, err)
 	
-}
\ No newline at end of file
+}
+
+func TestStandard850V4BatchToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	var standard850V4Batch Standard850V4Batch
+	standard850V4Batch.EnvelopeHeaderV3 = Standard850V4s[0].EnvelopeHeaderV3
+	standard850V4Batch.EnvelopeTrailerV3 = Standard850V4s[0].EnvelopeTrailerV3
+	for _, purchaseOrderNumber := range []string{"12345678", "12345679"} {
+		transaction := Standard850V4s[0].Transaction
+		transaction.PurchaseOrderNumber = purchaseOrderNumber
+		standard850V4Batch.PurchaseOrders = append(standard850V4Batch.PurchaseOrders, Standard850V4PurchaseOrder{
+			Transaction:  transaction,
+			LineItems:    append([]Standard850V4LineItem{}, Standard850V4s[0].LineItems...),
+			OtherCharges: append([]Standard850V4OtherCharge{}, Standard850V4s[0].OtherCharges...),
+		})
+	}
+
+	byteArrayPointer, err := standard850V4Batch.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer != nil {
+		err := ioutil.WriteFile("./examples/850v4batch-0.txt", *byteArrayPointer, 0644)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, 2, standard850V4Batch.EnvelopeTrailerV3.NumberOfDocuments)
+	assert.Equal(t, "09", standard850V4Batch.PurchaseOrders[1].Trailer.TrailerRecord)
+
+}
+
+func TestStandard850V4BatchFromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/850v4batch-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard850V4Batch Standard850V4Batch
+	err := standard850V4Batch.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, standard850V4Batch.EnvelopeTrailerV3.NumberOfDocuments)
+	if assert.Len(t, standard850V4Batch.PurchaseOrders, 2) {
+		assert.Equal(t, "12345678", standard850V4Batch.PurchaseOrders[0].Transaction.PurchaseOrderNumber)
+		assert.Equal(t, "12345679", standard850V4Batch.PurchaseOrders[1].Transaction.PurchaseOrderNumber)
+		assert.Len(t, standard850V4Batch.PurchaseOrders[1].LineItems, 2)
+		assert.Len(t, standard850V4Batch.PurchaseOrders[1].OtherCharges, 1)
+	}
+
+	standard850V4s := standard850V4Batch.Split(ctx)
+	if assert.Len(t, standard850V4s, 2) {
+		assert.Equal(t, "12345679", standard850V4s[1].Transaction.PurchaseOrderNumber)
+		assert.Equal(t, 1, standard850V4s[1].EnvelopeTrailerV3.NumberOfDocuments)
+	}
+
+}
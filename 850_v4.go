@@ -240,6 +240,7 @@ func (s *Standard850V4) FromBytes(ctx context.Context, req []byte) (error){
 
 	r := csv.NewReader(bytes.NewReader(req))
 	r.Comma = '\t'
+	r.FieldsPerRecord = -1
 
 	// Headerless
 	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
@@ -255,9 +256,13 @@ func (s *Standard850V4) FromBytes(ctx context.Context, req []byte) (error){
 
 	for {
 		var v struct{}
-		if err := dec.Decode(&v); err == io.EOF {
+		err := dec.Decode(&v)
+		if err == io.EOF {
 			break
 		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
 
 		// Record
 		var lineType string
@@ -0,0 +1,247 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+
+	"github.com/jszwec/csvutil"
+)
+
+// Standard850V4Batch holds several purchase orders in one envelope, each as
+// its own 01…09 group. Standard850V4 keeps only the last group of such a file.
+type Standard850V4Batch struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	PurchaseOrders    []Standard850V4PurchaseOrder
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard850V4PurchaseOrder struct {
+	Transaction  Standard850V4Transaction
+	LineItems    []Standard850V4LineItem
+	OtherCharges []Standard850V4OtherCharge
+	Trailer      Standard850V4Trailer
+}
+
+func (s *Standard850V4Batch) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "850"
+
+	// Purchase Orders
+	for purchaseOrderKey, purchaseOrder := range s.PurchaseOrders {
+		standard850V4 := Standard850V4{
+			Transaction:  purchaseOrder.Transaction,
+			LineItems:    purchaseOrder.LineItems,
+			OtherCharges: purchaseOrder.OtherCharges,
+			Trailer:      purchaseOrder.Trailer,
+		}
+		errPurchaseOrder := standard850V4.Prep(ctx)
+		if errPurchaseOrder != nil {
+			return errPurchaseOrder
+		}
+		s.PurchaseOrders[purchaseOrderKey].Transaction = standard850V4.Transaction
+		s.PurchaseOrders[purchaseOrderKey].Trailer = standard850V4.Trailer
+	}
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.PrepDocuments(ctx, len(s.PurchaseOrders))
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard850V4Batch) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Purchase Orders
+	for _, purchaseOrder := range s.PurchaseOrders {
+
+		// Transaction
+		errTransaction := enc.Encode(purchaseOrder.Transaction)
+		if errTransaction != nil {
+			return nil, errTransaction
+		}
+
+		// Line Items
+		for _, lineItem := range purchaseOrder.LineItems {
+			errLineItem := enc.Encode(lineItem)
+			if errLineItem != nil {
+				return nil, errLineItem
+			}
+		}
+
+		// Other Charges
+		for _, otherCharge := range purchaseOrder.OtherCharges {
+			errOtherCharge := enc.Encode(otherCharge)
+			if errOtherCharge != nil {
+				return nil, errOtherCharge
+			}
+		}
+
+		// Trailer
+		errTrailer := enc.Encode(purchaseOrder.Trailer)
+		if errTrailer != nil {
+			return nil, errTrailer
+		}
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard850V4Batch) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	var purchaseOrderCount int
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Purchase Order
+		switch lineType {
+		case "02", "06", "09":
+			if purchaseOrderCount <= 0 {
+				s.PurchaseOrders = append(s.PurchaseOrders, Standard850V4PurchaseOrder{})
+				purchaseOrderCount++
+			}
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard850V4Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.PurchaseOrders = append(s.PurchaseOrders, Standard850V4PurchaseOrder{Transaction: x})
+			purchaseOrderCount++
+		case "02":
+			var x Standard850V4LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.PurchaseOrders[purchaseOrderCount-1].LineItems = append(s.PurchaseOrders[purchaseOrderCount-1].LineItems, x)
+		case "06":
+			var x Standard850V4OtherCharge
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.PurchaseOrders[purchaseOrderCount-1].OtherCharges = append(s.PurchaseOrders[purchaseOrderCount-1].OtherCharges, x)
+		case "09":
+			var x Standard850V4Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.PurchaseOrders[purchaseOrderCount-1].Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// Split returns each purchase order as a Standard850V4 in its own copy of the
+// envelope.
+func (s *Standard850V4Batch) Split(ctx context.Context) []Standard850V4 {
+
+	var standard850V4s []Standard850V4
+	for _, purchaseOrder := range s.PurchaseOrders {
+		envelopeTrailerV3 := s.EnvelopeTrailerV3
+		envelopeTrailerV3.NumberOfDocuments = 1
+		standard850V4s = append(standard850V4s, Standard850V4{
+			EnvelopeHeaderV3:  s.EnvelopeHeaderV3,
+			Transaction:       purchaseOrder.Transaction,
+			LineItems:         purchaseOrder.LineItems,
+			OtherCharges:      purchaseOrder.OtherCharges,
+			Trailer:           purchaseOrder.Trailer,
+			EnvelopeTrailerV3: envelopeTrailerV3,
+		})
+	}
+
+	return standard850V4s
+}
@@ -0,0 +1,117 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard852V1s = []Standard852V1{
+		Standard852V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard852V1Transaction{
+				ReportNumber:             "W08",
+				ReportingPeriodStartDate: "20210221",
+				ReportingPeriodEndDate:   "20210227",
+				VendorID:                 "707738",
+				PurchaserAccountID:       "12345",
+			},
+			Locations: []Standard852V1Location{
+				Standard852V1Location{
+					StoreID:   "8976",
+					StoreName: "Estes Park",
+					LineItems: []Standard852V1LineItem{
+						Standard852V1LineItem{ItemIdentificationGTIN: "00707738003265", MasterStyle: "2002", ColorCode: "NAV", SizeCode: "S", QuantitySold: 4, QuantityOnHand: 10, QuantityOnOrder: 6},
+						Standard852V1LineItem{ItemIdentificationGTIN: "00707738001245", MasterStyle: "2002", ColorCode: "NVY", SizeCode: "M", QuantitySold: 2, QuantityOnHand: 5},
+					},
+				},
+				Standard852V1Location{
+					StoreID:   "8977",
+					StoreName: "Boulder",
+					LineItems: []Standard852V1LineItem{
+						Standard852V1LineItem{ItemIdentificationGTIN: "00707738003265", MasterStyle: "2002", ColorCode: "NAV", SizeCode: "S", QuantitySold: 1, QuantityOnHand: 3},
+						Standard852V1LineItem{ItemIdentificationGTIN: "00707738003272", MasterStyle: "2002", ColorCode: "NAV", SizeCode: "M", QuantitySold: 7, QuantityOnHand: 0, QuantityOnOrder: 12},
+					},
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard852V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard852V1Key, Standard852V1 := range Standard852V1s {
+		byteArrayPointer, err := Standard852V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/852-"+strconv.Itoa(Standard852V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 4, Standard852V1.Trailer.RecordCount)
+		assert.Equal(t, 14, Standard852V1.Trailer.TotalQuantitySold)
+		assert.Equal(t, 2, Standard852V1.Trailer.TotalLocationCount)
+	}
+
+}
+
+func TestStandard852V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/852-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard852V1 Standard852V1
+	err := standard852V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "20210227", standard852V1.Transaction.ReportingPeriodEndDate)
+	if assert.Len(t, standard852V1.Locations, 2) && assert.Len(t, standard852V1.Locations[1].LineItems, 2) {
+		assert.Equal(t, "8977", standard852V1.Locations[1].StoreID)
+		assert.Equal(t, 12, standard852V1.Locations[1].LineItems[1].QuantityOnOrder)
+	}
+
+}
+
+func TestStandard852V1Rollup(t *testing.T) {
+
+	ctx := context.Background()
+
+	styles := Standard852V1s[0].Rollup(ctx, Standard852V1RollupMasterStyle)
+	if assert.Len(t, styles, 1) {
+		assert.Equal(t, Standard852V1Rollup{MasterStyle: "2002", QuantitySold: 14, QuantityOnHand: 18, QuantityOnOrder: 18, StoreCount: 2}, styles[0])
+	}
+
+	colors := Standard852V1s[0].Rollup(ctx, Standard852V1RollupColorCode)
+	if assert.Len(t, colors, 2) {
+		assert.Equal(t, "NAV", colors[0].ColorCode)
+		assert.Equal(t, 12, colors[0].QuantitySold)
+		assert.Equal(t, 2, colors[0].StoreCount)
+		assert.Equal(t, 1, colors[1].StoreCount)
+	}
+
+	sizes := Standard852V1s[0].Rollup(ctx, Standard852V1RollupSizeCode)
+	if assert.Len(t, sizes, 3) {
+		assert.Equal(t, Standard852V1Rollup{MasterStyle: "2002", ColorCode: "NAV", SizeCode: "S", QuantitySold: 5, QuantityOnHand: 13, QuantityOnOrder: 6, StoreCount: 2}, sizes[0])
+	}
+
+}
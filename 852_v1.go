@@ -0,0 +1,489 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard852V1RollupMasterStyle = iota + 1
+	Standard852V1RollupColorCode
+	Standard852V1RollupSizeCode
+)
+
+type Standard852V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard852V1Transaction
+	Locations         []Standard852V1Location
+	Trailer           Standard852V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard852V1Transaction struct {
+	Header                   string
+	TransactionType          string
+	TransactionSetPurpose    string
+	VersionNumber            string
+	ReportNumber             string
+	ReportingPeriodStartDate string
+	ReportingPeriodEndDate   string
+	VendorID                 string
+	PurchaserAccountID       string
+}
+
+type Standard852V1Location struct {
+	LocationRecord string
+	StoreID        string
+	StoreName      string
+	LineItems      []Standard852V1LineItem `csv:"-"`
+}
+
+type Standard852V1LineItem struct {
+	DetailSectionLoopA     string
+	LineItemNumber         int
+	ItemIdentificationGTIN string
+	MasterStyle            string
+	ColorCode              string
+	SizeCode               string
+	UnitOfMeasure          string
+	QuantitySold           int
+	QuantityOnHand         int
+	QuantityOnOrder        int
+}
+
+type Standard852V1Trailer struct {
+	TrailerRecord        string
+	RecordCount          int
+	TotalQuantitySold    int
+	TotalQuantityOnHand  int
+	TotalQuantityOnOrder int
+	TotalLocationCount   int
+}
+
+type Standard852V1Rollup struct {
+	MasterStyle     string
+	ColorCode       string
+	SizeCode        string
+	QuantitySold    int
+	QuantityOnHand  int
+	QuantityOnOrder int
+	StoreCount      int
+}
+
+func (s *Standard852V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "852"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "852"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+
+	// Locations
+	var recordCount, totalQuantitySold, totalQuantityOnHand, totalQuantityOnOrder int
+	for locationKey, location := range s.Locations {
+		s.Locations[locationKey].LocationRecord = "05"
+
+		// Line Items
+		for lineItemKey, lineItem := range location.LineItems {
+			recordCount++
+			s.Locations[locationKey].LineItems[lineItemKey].DetailSectionLoopA = "02"
+			s.Locations[locationKey].LineItems[lineItemKey].LineItemNumber = recordCount
+			if lineItem.UnitOfMeasure == "" {
+				s.Locations[locationKey].LineItems[lineItemKey].UnitOfMeasure = "EA"
+			}
+			totalQuantitySold += lineItem.QuantitySold
+			totalQuantityOnHand += lineItem.QuantityOnHand
+			totalQuantityOnOrder += lineItem.QuantityOnOrder
+		}
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = recordCount
+	s.Trailer.TotalQuantitySold = totalQuantitySold
+	s.Trailer.TotalQuantityOnHand = totalQuantityOnHand
+	s.Trailer.TotalQuantityOnOrder = totalQuantityOnOrder
+	s.Trailer.TotalLocationCount = len(s.Locations)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard852V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Locations
+	for _, location := range s.Locations {
+		errLocation := enc.Encode(location)
+		if errLocation != nil {
+			return nil, errLocation
+		}
+
+		// Line Items
+		for _, lineItem := range location.LineItems {
+			errLineItem := enc.Encode(lineItem)
+			if errLineItem != nil {
+				return nil, errLineItem
+			}
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard852V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	var locationCount int
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard852V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "05":
+			var x Standard852V1Location
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Locations = append(s.Locations, x)
+			locationCount++
+		case "02":
+			var x Standard852V1LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			if locationCount <= 0 {
+				s.Locations = append(s.Locations, Standard852V1Location{})
+				locationCount++
+			}
+			s.Locations[locationCount-1].LineItems = append(s.Locations[locationCount-1].LineItems, x)
+		case "09":
+			var x Standard852V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// Rollup totals the activity of all stores by MasterStyle, by MasterStyle and
+// ColorCode, or by MasterStyle, ColorCode and SizeCode. Rollups are returned
+// in the order they first appear in the report.
+func (s *Standard852V1) Rollup(ctx context.Context, level int) []Standard852V1Rollup {
+
+	var rollups []Standard852V1Rollup
+	rollupKeys := map[Standard852V1Rollup]int{}
+	for _, location := range s.Locations {
+		locationKeys := map[int]bool{}
+		for _, lineItem := range location.LineItems {
+
+			// Key
+			key := Standard852V1Rollup{MasterStyle: lineItem.MasterStyle}
+			if level >= Standard852V1RollupColorCode {
+				key.ColorCode = lineItem.ColorCode
+			}
+			if level >= Standard852V1RollupSizeCode {
+				key.SizeCode = lineItem.SizeCode
+			}
+			rollupKey, ok := rollupKeys[key]
+			if !ok {
+				rollups = append(rollups, key)
+				rollupKey = len(rollups) - 1
+				rollupKeys[key] = rollupKey
+			}
+
+			// Totals
+			rollups[rollupKey].QuantitySold += lineItem.QuantitySold
+			rollups[rollupKey].QuantityOnHand += lineItem.QuantityOnHand
+			rollups[rollupKey].QuantityOnOrder += lineItem.QuantityOnOrder
+			if !locationKeys[rollupKey] {
+				rollups[rollupKey].StoreCount++
+				locationKeys[rollupKey] = true
+			}
+		}
+	}
+
+	return rollups
+}
+
+func (s *Standard852V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.ReportNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.ReportingPeriodStartDate = req[5]
+	}
+	if len(req) > 6 {
+		s.ReportingPeriodEndDate = req[6]
+	}
+	if len(req) > 7 {
+		s.VendorID = req[7]
+	}
+	if len(req) > 8 {
+		s.PurchaserAccountID = req[8]
+	}
+
+	return nil
+}
+
+func (s *Standard852V1Location) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.LocationRecord = req[0]
+	}
+	if len(req) > 1 {
+		s.StoreID = req[1]
+	}
+	if len(req) > 2 {
+		s.StoreName = req[2]
+	}
+
+	return nil
+}
+
+func (s *Standard852V1LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.ItemIdentificationGTIN = req[2]
+	}
+	if len(req) > 3 {
+		s.MasterStyle = req[3]
+	}
+	if len(req) > 4 {
+		s.ColorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.SizeCode = req[5]
+	}
+	if len(req) > 6 {
+		s.UnitOfMeasure = req[6]
+	}
+	if len(req) > 7 {
+		if req[7] != "" {
+			quantitySold, err := strconv.Atoi(req[7])
+			if err != nil {
+				return err
+			}
+			s.QuantitySold = quantitySold
+		}
+	}
+	if len(req) > 8 {
+		if req[8] != "" {
+			quantityOnHand, err := strconv.Atoi(req[8])
+			if err != nil {
+				return err
+			}
+			s.QuantityOnHand = quantityOnHand
+		}
+	}
+	if len(req) > 9 {
+		if req[9] != "" {
+			quantityOnOrder, err := strconv.Atoi(req[9])
+			if err != nil {
+				return err
+			}
+			s.QuantityOnOrder = quantityOnOrder
+		}
+	}
+
+	return nil
+}
+
+func (s *Standard852V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			totalQuantitySold, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantitySold = totalQuantitySold
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			totalQuantityOnHand, err := strconv.Atoi(req[3])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityOnHand = totalQuantityOnHand
+		}
+	}
+	if len(req) > 4 {
+		if req[4] != "" {
+			totalQuantityOnOrder, err := strconv.Atoi(req[4])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityOnOrder = totalQuantityOnOrder
+		}
+	}
+	if len(req) > 5 {
+		if req[5] != "" {
+			totalLocationCount, err := strconv.Atoi(req[5])
+			if err != nil {
+				return err
+			}
+			s.TotalLocationCount = totalLocationCount
+		}
+	}
+
+	return nil
+}
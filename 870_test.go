@@ -0,0 +1,127 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandard870V1FromDocuments(t *testing.T) {
+
+	ctx := context.Background()
+
+	orders := []Standard850V4{
+		Standard850V4{
+			Transaction: Standard850V4Transaction{
+				PurchaseOrderNumber: "12345678",
+				PODate:              "20210220",
+				RequestedShipDate:   "20210301",
+			},
+			LineItems: []Standard850V4LineItem{
+				Standard850V4LineItem{LineItemNumber: 1, ItemIdentificationGTIN: "00821780002660", QuantityOrdered: 12},
+				Standard850V4LineItem{LineItemNumber: 2, ItemIdentificationGTIN: "00821780002799", QuantityOrdered: 6},
+			},
+		},
+		Standard850V4{
+			Transaction: Standard850V4Transaction{
+				PurchaseOrderNumber: "12345679",
+				RequestedShipDate:   "20210305",
+			},
+			LineItems: []Standard850V4LineItem{
+				Standard850V4LineItem{LineItemNumber: 1, ItemIdentificationGTIN: "00821780002660", QuantityOrdered: 24},
+			},
+		},
+		Standard850V4{
+			Transaction: Standard850V4Transaction{
+				TransactionSetPurpose: "01",
+				PurchaseOrderNumber:   "12345680",
+			},
+			LineItems: []Standard850V4LineItem{
+				Standard850V4LineItem{LineItemNumber: 1, ItemIdentificationGTIN: "00821780002660", QuantityOrdered: 1},
+			},
+		},
+	}
+
+	shipments := []Standard856V7{
+		Standard856V7{
+			Transaction: Standard856V7Transaction{
+				ShipmentDate: "20210226",
+			},
+			Pallets: []Standard856V7Pallet{
+				Standard856V7Pallet{
+					Shipments: []Standard856V7Shipment{
+						Standard856V7Shipment{
+							BuyersPurchaseOrderNumber: "12345678",
+							LineItems: []Standard856V7LineItem{
+								Standard856V7LineItem{ItemIdentificationGTIN: "00821780002660", QuantityShipped: 12},
+								Standard856V7LineItem{ItemIdentificationGTIN: "00821780002799", QuantityShipped: 6},
+							},
+						},
+						Standard856V7Shipment{
+							BuyersPurchaseOrderNumber: "12345679",
+							LineItems: []Standard856V7LineItem{
+								Standard856V7LineItem{ItemIdentificationGTIN: "00821780002660", QuantityShipped: 10},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	standard870V1 := Standard870V1{
+		EnvelopeHeaderV3: EnvelopeHeaderV3{
+			InterchangeID: "202102268484912",
+			ReceiverID:    "123456789",
+			SenderID:      "383601069",
+		},
+		EnvelopeTrailerV3: EnvelopeTrailerV3{
+			InterchangeID: "202102268484912",
+		},
+	}
+	err := standard870V1.FromDocuments(ctx, orders, shipments)
+	assert.Nil(t, err)
+
+	if assert.Len(t, standard870V1.Orders, 3) {
+		assert.Equal(t, Standard870V1StatusShipped, standard870V1.Orders[0].StatusCode)
+		assert.Equal(t, "20210226", standard870V1.Orders[0].ShipmentDate)
+		assert.Equal(t, Standard870V1StatusBackordered, standard870V1.Orders[1].StatusCode)
+		assert.Equal(t, 14, standard870V1.Orders[1].LineItems[0].QuantityRemaining)
+		assert.Equal(t, "20210305", standard870V1.Orders[1].LineItems[0].ExpectedShipDate)
+		assert.Equal(t, Standard870V1StatusCancelled, standard870V1.Orders[2].StatusCode)
+	}
+
+	byteArrayPointer, err := standard870V1.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer != nil {
+		err := ioutil.WriteFile("./examples/870-0.txt", *byteArrayPointer, 0644)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 4, standard870V1.Trailer.RecordCount)
+	assert.Equal(t, 43, standard870V1.Trailer.TotalQuantityOrdered)
+	assert.Equal(t, 28, standard870V1.Trailer.TotalQuantityShipped)
+
+}
+
+func TestStandard870V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/870-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard870V1 Standard870V1
+	err := standard870V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	if assert.Len(t, standard870V1.Orders, 3) && assert.Len(t, standard870V1.Orders[1].LineItems, 1) {
+		assert.Equal(t, "12345679", standard870V1.Orders[1].PurchaseOrderNumber)
+		assert.Equal(t, 10, standard870V1.Orders[1].LineItems[0].QuantityShipped)
+		assert.Equal(t, Standard870V1StatusBackordered, standard870V1.Orders[1].LineItems[0].StatusCode)
+	}
+
+}
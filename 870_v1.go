@@ -0,0 +1,557 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard870V1StatusOpen        = "OP"
+	Standard870V1StatusPicked      = "PK"
+	Standard870V1StatusShipped     = "SH"
+	Standard870V1StatusCancelled   = "CA"
+	Standard870V1StatusBackordered = "BO"
+)
+
+// Standard870V1Statuses lists the order and line status codes.
+var Standard870V1Statuses = []string{Standard870V1StatusOpen, Standard870V1StatusPicked, Standard870V1StatusShipped, Standard870V1StatusCancelled, Standard870V1StatusBackordered}
+
+type Standard870V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard870V1Transaction
+	Orders            []Standard870V1Order
+	Trailer           Standard870V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard870V1Transaction struct {
+	Header                string
+	TransactionType       string
+	TransactionSetPurpose string
+	VersionNumber         string
+	ReportNumber          string
+	ReportDate            string
+	ReportTime            string
+	VendorID              string
+	PurchaserAccountID    string
+}
+
+type Standard870V1Order struct {
+	DetailSectionLoopA   string
+	PurchaseOrderNumber  string
+	PODate               string
+	StoreID              string
+	DistributionCenterID string
+	StatusCode           string
+	ExpectedShipDate     string
+	ShipmentDate         string
+	CancelDate           string
+	ExpectedDeliveryDate string
+	LineItems            []Standard870V1LineItem `csv:"-"`
+}
+
+type Standard870V1LineItem struct {
+	DetailSectionLoopB            string
+	LineItemNumber                int
+	ItemIdentificationGTIN        string
+	MasterStyle                   string
+	ColorCode                     string
+	SizeCode                      string
+	UnitOrBasisForMeasurementCode string
+	QuantityOrdered               int
+	QuantityShipped               int
+	QuantityRemaining             int
+	StatusCode                    string
+	ExpectedShipDate              string
+}
+
+type Standard870V1Trailer struct {
+	TrailerRecord        string
+	RecordCount          int
+	TotalQuantityOrdered int
+	TotalQuantityShipped int
+}
+
+func (s *Standard870V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "870"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "870"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	s.Transaction.ReportDate = time.Now().Format("20060102")
+	s.Transaction.ReportTime = time.Now().Format("150405")
+
+	// Orders
+	var recordCount, totalQuantityOrdered, totalQuantityShipped int
+	for orderKey, order := range s.Orders {
+		s.Orders[orderKey].DetailSectionLoopA = "02"
+
+		// Line Items
+		for lineItemKey, lineItem := range order.LineItems {
+			recordCount++
+			s.Orders[orderKey].LineItems[lineItemKey].DetailSectionLoopB = "03"
+			if lineItem.LineItemNumber == 0 {
+				s.Orders[orderKey].LineItems[lineItemKey].LineItemNumber = lineItemKey + 1
+			}
+			if lineItem.UnitOrBasisForMeasurementCode == "" {
+				s.Orders[orderKey].LineItems[lineItemKey].UnitOrBasisForMeasurementCode = "EA"
+			}
+			totalQuantityOrdered += lineItem.QuantityOrdered
+			totalQuantityShipped += lineItem.QuantityShipped
+		}
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = recordCount
+	s.Trailer.TotalQuantityOrdered = totalQuantityOrdered
+	s.Trailer.TotalQuantityShipped = totalQuantityShipped
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard870V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Orders
+	for _, order := range s.Orders {
+		errOrder := enc.Encode(order)
+		if errOrder != nil {
+			return nil, errOrder
+		}
+
+		// Line Items
+		for _, lineItem := range order.LineItems {
+			errLineItem := enc.Encode(lineItem)
+			if errLineItem != nil {
+				return nil, errLineItem
+			}
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard870V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	var orderCount int
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard870V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard870V1Order
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Orders = append(s.Orders, x)
+			orderCount++
+		case "03":
+			var x Standard870V1LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			if orderCount <= 0 {
+				s.Orders = append(s.Orders, Standard870V1Order{})
+				orderCount++
+			}
+			s.Orders[orderCount-1].LineItems = append(s.Orders[orderCount-1].LineItems, x)
+		case "09":
+			var x Standard870V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// FromDocuments fills the report with the status of each purchase order,
+// counting the quantities shipped against it on the given ship notices. Ship
+// notices carry nothing about picking, so Picked is left for callers and
+// warehouse feeds to set.
+func (s *Standard870V1) FromDocuments(ctx context.Context, orders []Standard850V4, shipments []Standard856V7) error {
+
+	// Shipped
+	type shippedKey struct {
+		PurchaseOrderNumber    string
+		ItemIdentificationGTIN string
+	}
+	shipped := map[shippedKey]int{}
+	shipmentDates := map[string]string{}
+	for _, shipment := range shipments {
+		for _, pallet := range shipment.Pallets {
+			for _, palletShipment := range pallet.Shipments {
+				for _, lineItem := range palletShipment.LineItems {
+					purchaseOrderNumber := lineItem.BuyersPurchaseOrderNumber
+					if purchaseOrderNumber == "" {
+						purchaseOrderNumber = palletShipment.BuyersPurchaseOrderNumber
+					}
+					shipped[shippedKey{purchaseOrderNumber, lineItem.ItemIdentificationGTIN}] += lineItem.QuantityShipped
+					if shipment.Transaction.ShipmentDate > shipmentDates[purchaseOrderNumber] {
+						shipmentDates[purchaseOrderNumber] = shipment.Transaction.ShipmentDate
+					}
+				}
+			}
+		}
+	}
+
+	// Orders
+	s.Orders = nil
+	for _, order := range orders {
+		cancelled := order.Transaction.TransactionSetPurpose == "01"
+		x := Standard870V1Order{
+			PurchaseOrderNumber:  order.Transaction.PurchaseOrderNumber,
+			PODate:               order.Transaction.PODate,
+			StoreID:              order.Transaction.StoreID,
+			DistributionCenterID: order.Transaction.DistributionCenterID,
+			ExpectedShipDate:     order.Transaction.RequestedShipDate,
+			ShipmentDate:         shipmentDates[order.Transaction.PurchaseOrderNumber],
+			CancelDate:           order.Transaction.CancelDate,
+		}
+
+		// Line Items
+		var quantityOrdered, quantityShipped int
+		for _, lineItem := range order.LineItems {
+			key := shippedKey{order.Transaction.PurchaseOrderNumber, lineItem.ItemIdentificationGTIN}
+			lineItemShipped := shipped[key]
+			if lineItemShipped > lineItem.QuantityOrdered {
+				lineItemShipped = lineItem.QuantityOrdered
+			}
+			shipped[key] -= lineItemShipped
+
+			y := Standard870V1LineItem{
+				LineItemNumber:                lineItem.LineItemNumber,
+				ItemIdentificationGTIN:        lineItem.ItemIdentificationGTIN,
+				MasterStyle:                   lineItem.MasterStyle,
+				ColorCode:                     lineItem.ColorCode,
+				SizeCode:                      lineItem.SizeCode,
+				UnitOrBasisForMeasurementCode: lineItem.UnitOrBasisForMeasurementCode,
+				QuantityOrdered:               lineItem.QuantityOrdered,
+				QuantityShipped:               lineItemShipped,
+				QuantityRemaining:             lineItem.QuantityOrdered - lineItemShipped,
+				StatusCode:                    standard870V1Status(cancelled, lineItem.QuantityOrdered, lineItemShipped),
+				ExpectedShipDate:              order.Transaction.RequestedShipDate,
+			}
+			x.LineItems = append(x.LineItems, y)
+			quantityOrdered += lineItem.QuantityOrdered
+			quantityShipped += lineItemShipped
+		}
+		x.StatusCode = standard870V1Status(cancelled, quantityOrdered, quantityShipped)
+
+		s.Orders = append(s.Orders, x)
+	}
+
+	return nil
+}
+
+// standard870V1Status reports a partly shipped line or order as backordered:
+// what has not shipped yet is still owed on the purchase order.
+func standard870V1Status(cancelled bool, quantityOrdered int, quantityShipped int) string {
+
+	switch {
+	case cancelled:
+		return Standard870V1StatusCancelled
+	case quantityShipped > 0 && quantityShipped >= quantityOrdered:
+		return Standard870V1StatusShipped
+	case quantityShipped > 0:
+		return Standard870V1StatusBackordered
+	default:
+		return Standard870V1StatusOpen
+	}
+}
+
+func (s *Standard870V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.ReportNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.ReportDate = req[5]
+	}
+	if len(req) > 6 {
+		s.ReportTime = req[6]
+	}
+	if len(req) > 7 {
+		s.VendorID = req[7]
+	}
+	if len(req) > 8 {
+		s.PurchaserAccountID = req[8]
+	}
+
+	return nil
+}
+
+func (s *Standard870V1Order) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		s.PurchaseOrderNumber = req[1]
+	}
+	if len(req) > 2 {
+		s.PODate = req[2]
+	}
+	if len(req) > 3 {
+		s.StoreID = req[3]
+	}
+	if len(req) > 4 {
+		s.DistributionCenterID = req[4]
+	}
+	if len(req) > 5 {
+		s.StatusCode = req[5]
+	}
+	if len(req) > 6 {
+		s.ExpectedShipDate = req[6]
+	}
+	if len(req) > 7 {
+		s.ShipmentDate = req[7]
+	}
+	if len(req) > 8 {
+		s.CancelDate = req[8]
+	}
+	if len(req) > 9 {
+		s.ExpectedDeliveryDate = req[9]
+	}
+
+	return nil
+}
+
+func (s *Standard870V1LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopB = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.ItemIdentificationGTIN = req[2]
+	}
+	if len(req) > 3 {
+		s.MasterStyle = req[3]
+	}
+	if len(req) > 4 {
+		s.ColorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.SizeCode = req[5]
+	}
+	if len(req) > 6 {
+		s.UnitOrBasisForMeasurementCode = req[6]
+	}
+	if len(req) > 7 {
+		if req[7] != "" {
+			quantityOrdered, err := strconv.Atoi(req[7])
+			if err != nil {
+				return err
+			}
+			s.QuantityOrdered = quantityOrdered
+		}
+	}
+	if len(req) > 8 {
+		if req[8] != "" {
+			quantityShipped, err := strconv.Atoi(req[8])
+			if err != nil {
+				return err
+			}
+			s.QuantityShipped = quantityShipped
+		}
+	}
+	if len(req) > 9 {
+		if req[9] != "" {
+			quantityRemaining, err := strconv.Atoi(req[9])
+			if err != nil {
+				return err
+			}
+			s.QuantityRemaining = quantityRemaining
+		}
+	}
+	if len(req) > 10 {
+		s.StatusCode = req[10]
+	}
+	if len(req) > 11 {
+		s.ExpectedShipDate = req[11]
+	}
+
+	return nil
+}
+
+func (s *Standard870V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			totalQuantityOrdered, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityOrdered = totalQuantityOrdered
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			totalQuantityShipped, err := strconv.Atoi(req[3])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityShipped = totalQuantityShipped
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard940V2s = []Standard940V2{
+		Standard940V2{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard940V2Transaction{
+				PurchaseOrderTypeCode:                "SA",
+				PurchaseOrderNumber:                  "12345678",
+				CurrencyCode:                         "USD",
+				PurchaserAccountID:                   "12345",
+				VendorID:                             "707738",
+				DistributionCenterID:                 "CLT1",
+				FOBPaymentInstructions:               "PP",
+				DropShipCode:                         "Y",
+				DeliverToCompanyName:                 "Overlook Hotel",
+				DeliverToContactName:                 "Jack Torrance",
+				DeliverToAddress1:                    "333 E Wonderview Ave",
+				DeliverToCityName:                    "Estes Park",
+				DeliverToStateCode:                   "CO",
+				DeliverToPostalCode:                  "80517",
+				DeliverToCountyProvinceTownTerritory: "Larimer",
+				DeliveryServiceLevel:                 "GROUND",
+				DeliverToReceiversPhoneNumber:        "9705771000",
+				CODForMerchandise:                    "Y",
+				ReceiversEmailAddress:                "jack@overlook.example",
+				TrackingID:                           "1Z5R9A10341241218",
+				DeliverToCommercialOrResidentialSite: "R",
+				CODTagsIndicator:                     "Y",
+			},
+			LineItems: []Standard940V2LineItem{
+				Standard940V2LineItem{
+					ItemIdentificationGTIN: "00821780002660",
+					QuantityOrdered:        12,
+					PurchaseUnitPrice:      185,
+				},
+				Standard940V2LineItem{
+					ItemIdentificationGTIN: "00821780002799",
+					QuantityOrdered:        6,
+					PurchaseUnitPrice:      185,
+				},
+			},
+			OtherCharges: []Standard940V2OtherCharge{
+				Standard940V2OtherCharge{
+					OtherChargeDescription: "COD FEE",
+					OtherChargeAmount:      200,
+				},
+			},
+			Trailer: Standard940V2Trailer{
+				NumberOfCases: 2,
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard940V2ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard940V2Key, Standard940V2 := range Standard940V2s {
+		byteArrayPointer, err := Standard940V2.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/940v2-"+strconv.Itoa(Standard940V2Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 18, Standard940V2.Trailer.TotalQuantityOrdered)
+		assert.Equal(t, 3330, Standard940V2.Trailer.TotalMonetaryValue)
+		assert.Equal(t, 3530, Standard940V2.Trailer.PurchaseOrderTotalAmount)
+		assert.Equal(t, "35.3000", Standard940V2.Trailer.PurchaseOrderTotalAmountFormatted)
+		assert.Equal(t, 2220, Standard940V2.LineItems[0].TotalMonetaryAmountOfLineItem)
+	}
+
+}
+
+func TestStandard940V2FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/940v2-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard940V2 Standard940V2
+	err := standard940V2.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Y", standard940V2.Transaction.DropShipCode)
+	assert.Equal(t, "Larimer", standard940V2.Transaction.DeliverToCountyProvinceTownTerritory)
+	assert.Equal(t, "jack@overlook.example", standard940V2.Transaction.ReceiversEmailAddress)
+	assert.Equal(t, "1Z5R9A10341241218", standard940V2.Transaction.TrackingID)
+	assert.Equal(t, "Y", standard940V2.Transaction.CODTagsIndicator)
+	if assert.Len(t, standard940V2.LineItems, 2) && assert.Len(t, standard940V2.OtherCharges, 1) {
+		assert.Equal(t, 185, standard940V2.LineItems[1].PurchaseUnitPrice)
+		assert.Equal(t, 200, standard940V2.OtherCharges[0].OtherChargeAmount)
+	}
+	assert.Equal(t, 18, standard940V2.Trailer.TotalQuantityOrdered)
+	assert.Equal(t, 3330, standard940V2.Trailer.TotalMonetaryValue)
+	assert.Equal(t, 200, standard940V2.Trailer.TotalMonetaryValueOfOtherCharges)
+	assert.Equal(t, 2, standard940V2.Trailer.NumberOfCases)
+	assert.Equal(t, 3530, standard940V2.Trailer.PurchaseOrderTotalAmount)
+
+}
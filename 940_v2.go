@@ -0,0 +1,661 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+// Standard940V2 carries the full 940 field set, including the parcel and COD
+// fields that Standard940V1 leaves out, and computes its trailer totals. Its
+// header record also adds a DistributionCenterID column after StoreID, so the
+// records are not laid out the same as in version 1.0.
+type Standard940V2 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard940V2Transaction
+	LineItems         []Standard940V2LineItem
+	OtherCharges      []Standard940V2OtherCharge
+	Trailer           Standard940V2Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard940V2Transaction struct {
+	Header                                     string
+	TransactionType                            string
+	TransactionSetPurpose                      string
+	VersionNumber                              string
+	PurchaseOrderTypeCode                      string
+	PurchaseOrderNumber                        string
+	ReleaseNumber                              string
+	PODate                                     string
+	POTime                                     string
+	ContractNumber                             string
+	CurrencyCode                               string
+	PurchaserAccountID                         string
+	StoreID                                    string
+	DistributionCenterID                       string
+	VendorID                                   string
+	ContactNameNumber                          string
+	FOBPaymentInstructions                     string
+	SalesRequirementCodeShipment               string
+	SalesRequirementCodeTruckLoad              string
+	SalesRequirementCodeShipDate               string
+	SalesRequirementCodeConsignmentOrShipBlind string
+	PaymentTermsDiscountOffered                string
+	PaymentTermsDiscountDays                   string
+	PaymentDueInNumberOfDaysWithoutDiscount    string
+	SpecificPaymentDate                        string
+	LiteralOfPaymentTerms                      string
+	RequestedShipDate                          string
+	CancelDate                                 string
+	CarrierRoutingDetails                      string
+	DeliverToCompanyName                       string
+	DeliverToContactName                       string
+	DeliverToAddress1                          string
+	DeliverToAddress2                          string
+	DeliverToCityName                          string
+	DeliverToStateCode                         string
+	DeliverToPostalCode                        string
+	DeliverToCountryCode                       string
+	DropShipCode                               string
+	SpecialDeliveryInstructions                string
+	SpecialOrderInstructions                   string
+	DeliverToCountyProvinceTownTerritory       string
+	PromotionalCode                            string
+	DeliveryServiceLevel                       string
+	DeliverToReceiversPhoneNumber              string
+	CustomerPONumber                           string
+	CODForMerchandise                          string
+	ReceiversEmailAddress                      string
+	AccountNumber                              string
+	NameOfAccount                              string
+	TrackingID                                 string
+	PurchasersAccountID                        string
+	DeliverToCommercialOrResidentialSite       string
+	CODTagsIndicator                           string
+	ThirdPartyAccountNumber                    string
+}
+
+type Standard940V2LineItem struct {
+	DetailSectionLoopA                     string
+	LineItemNumber                         int
+	ItemIdentificationGTIN                 string
+	MasterStyle                            string
+	ColorCode                              string
+	SizeCode                               string
+	QuantityOrdered                        int
+	UnitOrBasisForMeasurementCode          string
+	PurchaseUnitPrice                      int `csv:"-"`
+	PurchaseUnitPriceFormatted             string
+	TotalMonetaryAmountOfLineItem          int `csv:"-"`
+	TotalMonetaryAmountOfLineItemFormatted string
+}
+
+type Standard940V2OtherCharge struct {
+	OtherChargesRecord            string
+	LineItemNumberForOtherCharges int
+	OtherChargeDescription        string
+	OtherChargeAmount             int `csv:"-"`
+	OtherChargeAmountFormatted    string
+}
+
+type Standard940V2Trailer struct {
+	TrailerRecord                             string
+	RecordCount                               int
+	TotalQuantityOrdered                      int
+	TotalMonetaryValue                        int `csv:"-"`
+	TotalMonetaryValueFormatted               string
+	TotalMonetaryValueOfOtherCharges          int `csv:"-"`
+	TotalMonetaryValueOfOtherChargesFormatted string
+	NumberOfCases                             int
+	PurchaseOrderTotalAmount                  int `csv:"-"`
+	PurchaseOrderTotalAmountFormatted         string
+}
+
+// Prep computes the line amounts that are not set, and the trailer totals from
+// the line items and other charges. The purchase order total is what a COD
+// order collects on delivery.
+func (s *Standard940V2) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "940"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "940"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "2.0"
+	if s.Transaction.PODate == "" {
+		s.Transaction.PODate = time.Now().Format("20060102")
+	}
+
+	// Line Items
+	var totalQuantityOrdered, totalMonetaryValue int
+	for lineItemKey, lineItem := range s.LineItems {
+		if lineItem.TotalMonetaryAmountOfLineItem == 0 {
+			lineItem.TotalMonetaryAmountOfLineItem = lineItem.PurchaseUnitPrice * lineItem.QuantityOrdered
+		}
+		s.LineItems[lineItemKey].DetailSectionLoopA = "02"
+		s.LineItems[lineItemKey].LineItemNumber = lineItemKey + 1
+		if lineItem.UnitOrBasisForMeasurementCode == "" {
+			s.LineItems[lineItemKey].UnitOrBasisForMeasurementCode = "EA"
+		}
+		s.LineItems[lineItemKey].PurchaseUnitPriceFormatted = formatAmount(lineItem.PurchaseUnitPrice)
+		s.LineItems[lineItemKey].TotalMonetaryAmountOfLineItem = lineItem.TotalMonetaryAmountOfLineItem
+		s.LineItems[lineItemKey].TotalMonetaryAmountOfLineItemFormatted = formatAmount(lineItem.TotalMonetaryAmountOfLineItem)
+		totalQuantityOrdered += lineItem.QuantityOrdered
+		totalMonetaryValue += lineItem.TotalMonetaryAmountOfLineItem
+	}
+
+	// Other Charges
+	var totalMonetaryValueOfOtherCharges int
+	for otherChargeKey, otherCharge := range s.OtherCharges {
+		s.OtherCharges[otherChargeKey].OtherChargesRecord = "06"
+		s.OtherCharges[otherChargeKey].LineItemNumberForOtherCharges = otherChargeKey + 1 + 10
+		s.OtherCharges[otherChargeKey].OtherChargeAmountFormatted = formatAmount(otherCharge.OtherChargeAmount)
+		totalMonetaryValueOfOtherCharges += otherCharge.OtherChargeAmount
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.LineItems)
+	s.Trailer.TotalQuantityOrdered = totalQuantityOrdered
+	s.Trailer.TotalMonetaryValue = totalMonetaryValue
+	s.Trailer.TotalMonetaryValueFormatted = formatAmount(totalMonetaryValue)
+	s.Trailer.TotalMonetaryValueOfOtherCharges = totalMonetaryValueOfOtherCharges
+	s.Trailer.TotalMonetaryValueOfOtherChargesFormatted = formatAmount(totalMonetaryValueOfOtherCharges)
+	s.Trailer.PurchaseOrderTotalAmount = totalMonetaryValue + totalMonetaryValueOfOtherCharges
+	s.Trailer.PurchaseOrderTotalAmountFormatted = formatAmount(s.Trailer.PurchaseOrderTotalAmount)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard940V2) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		errLineItem := enc.Encode(lineItem)
+		if errLineItem != nil {
+			return nil, errLineItem
+		}
+	}
+
+	// Other Charges
+	for _, otherCharge := range s.OtherCharges {
+		errOtherCharge := enc.Encode(otherCharge)
+		if errOtherCharge != nil {
+			return nil, errOtherCharge
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard940V2) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard940V2Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard940V2LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.LineItems = append(s.LineItems, x)
+		case "06":
+			var x Standard940V2OtherCharge
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.OtherCharges = append(s.OtherCharges, x)
+		case "09":
+			var x Standard940V2Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+func (s *Standard940V2Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.PurchaseOrderTypeCode = req[4]
+	}
+	if len(req) > 5 {
+		s.PurchaseOrderNumber = req[5]
+	}
+	if len(req) > 6 {
+		s.ReleaseNumber = req[6]
+	}
+	if len(req) > 7 {
+		s.PODate = req[7]
+	}
+	if len(req) > 8 {
+		s.POTime = req[8]
+	}
+	if len(req) > 9 {
+		s.ContractNumber = req[9]
+	}
+	if len(req) > 10 {
+		s.CurrencyCode = req[10]
+	}
+	if len(req) > 11 {
+		s.PurchaserAccountID = req[11]
+	}
+	if len(req) > 12 {
+		s.StoreID = req[12]
+	}
+	if len(req) > 13 {
+		s.DistributionCenterID = req[13]
+	}
+	if len(req) > 14 {
+		s.VendorID = req[14]
+	}
+	if len(req) > 15 {
+		s.ContactNameNumber = req[15]
+	}
+	if len(req) > 16 {
+		s.FOBPaymentInstructions = req[16]
+	}
+	if len(req) > 17 {
+		s.SalesRequirementCodeShipment = req[17]
+	}
+	if len(req) > 18 {
+		s.SalesRequirementCodeTruckLoad = req[18]
+	}
+	if len(req) > 19 {
+		s.SalesRequirementCodeShipDate = req[19]
+	}
+	if len(req) > 20 {
+		s.SalesRequirementCodeConsignmentOrShipBlind = req[20]
+	}
+	if len(req) > 21 {
+		s.PaymentTermsDiscountOffered = req[21]
+	}
+	if len(req) > 22 {
+		s.PaymentTermsDiscountDays = req[22]
+	}
+	if len(req) > 23 {
+		s.PaymentDueInNumberOfDaysWithoutDiscount = req[23]
+	}
+	if len(req) > 24 {
+		s.SpecificPaymentDate = req[24]
+	}
+	if len(req) > 25 {
+		s.LiteralOfPaymentTerms = req[25]
+	}
+	if len(req) > 26 {
+		s.RequestedShipDate = req[26]
+	}
+	if len(req) > 27 {
+		s.CancelDate = req[27]
+	}
+	if len(req) > 28 {
+		s.CarrierRoutingDetails = req[28]
+	}
+	if len(req) > 29 {
+		s.DeliverToCompanyName = req[29]
+	}
+	if len(req) > 30 {
+		s.DeliverToContactName = req[30]
+	}
+	if len(req) > 31 {
+		s.DeliverToAddress1 = req[31]
+	}
+	if len(req) > 32 {
+		s.DeliverToAddress2 = req[32]
+	}
+	if len(req) > 33 {
+		s.DeliverToCityName = req[33]
+	}
+	if len(req) > 34 {
+		s.DeliverToStateCode = req[34]
+	}
+	if len(req) > 35 {
+		s.DeliverToPostalCode = req[35]
+	}
+	if len(req) > 36 {
+		s.DeliverToCountryCode = req[36]
+	}
+	if len(req) > 37 {
+		s.DropShipCode = req[37]
+	}
+	if len(req) > 38 {
+		s.SpecialDeliveryInstructions = req[38]
+	}
+	if len(req) > 39 {
+		s.SpecialOrderInstructions = req[39]
+	}
+	if len(req) > 40 {
+		s.DeliverToCountyProvinceTownTerritory = req[40]
+	}
+	if len(req) > 41 {
+		s.PromotionalCode = req[41]
+	}
+	if len(req) > 42 {
+		s.DeliveryServiceLevel = req[42]
+	}
+	if len(req) > 43 {
+		s.DeliverToReceiversPhoneNumber = req[43]
+	}
+	if len(req) > 44 {
+		s.CustomerPONumber = req[44]
+	}
+	if len(req) > 45 {
+		s.CODForMerchandise = req[45]
+	}
+	if len(req) > 46 {
+		s.ReceiversEmailAddress = req[46]
+	}
+	if len(req) > 47 {
+		s.AccountNumber = req[47]
+	}
+	if len(req) > 48 {
+		s.NameOfAccount = req[48]
+	}
+	if len(req) > 49 {
+		s.TrackingID = req[49]
+	}
+	if len(req) > 50 {
+		s.PurchasersAccountID = req[50]
+	}
+	if len(req) > 51 {
+		s.DeliverToCommercialOrResidentialSite = req[51]
+	}
+	if len(req) > 52 {
+		s.CODTagsIndicator = req[52]
+	}
+	if len(req) > 53 {
+		s.ThirdPartyAccountNumber = req[53]
+	}
+
+	return nil
+}
+
+func (s *Standard940V2LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.ItemIdentificationGTIN = req[2]
+	}
+	if len(req) > 3 {
+		s.MasterStyle = req[3]
+	}
+	if len(req) > 4 {
+		s.ColorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.SizeCode = req[5]
+	}
+	if len(req) > 6 {
+		if req[6] != "" {
+			quantityOrdered, err := strconv.Atoi(req[6])
+			if err != nil {
+				return err
+			}
+			s.QuantityOrdered = quantityOrdered
+		}
+	}
+	if len(req) > 7 {
+		s.UnitOrBasisForMeasurementCode = req[7]
+	}
+	if len(req) > 8 {
+		if req[8] != "" {
+			purchaseUnitPrice, err := parseAmount(req[8])
+			if err != nil {
+				return err
+			}
+			s.PurchaseUnitPrice = purchaseUnitPrice
+		}
+	}
+	if len(req) > 9 {
+		if req[9] != "" {
+			totalMonetaryAmountOfLineItem, err := parseAmount(req[9])
+			if err != nil {
+				return err
+			}
+			s.TotalMonetaryAmountOfLineItem = totalMonetaryAmountOfLineItem
+		}
+	}
+
+	return nil
+}
+
+func (s *Standard940V2OtherCharge) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.OtherChargesRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumberForOtherCharges, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumberForOtherCharges = lineItemNumberForOtherCharges
+		}
+	}
+	if len(req) > 2 {
+		s.OtherChargeDescription = req[2]
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			otherChargeAmount, err := parseAmount(req[3])
+			if err != nil {
+				return err
+			}
+			s.OtherChargeAmount = otherChargeAmount
+		}
+	}
+
+	return nil
+}
+
+func (s *Standard940V2Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			totalQuantityOrdered, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityOrdered = totalQuantityOrdered
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			totalMonetaryValue, err := parseAmount(req[3])
+			if err != nil {
+				return err
+			}
+			s.TotalMonetaryValue = totalMonetaryValue
+		}
+	}
+	if len(req) > 4 {
+		if req[4] != "" {
+			totalMonetaryValueOfOtherCharges, err := parseAmount(req[4])
+			if err != nil {
+				return err
+			}
+			s.TotalMonetaryValueOfOtherCharges = totalMonetaryValueOfOtherCharges
+		}
+	}
+	if len(req) > 5 {
+		if req[5] != "" {
+			numberOfCases, err := strconv.Atoi(req[5])
+			if err != nil {
+				return err
+			}
+			s.NumberOfCases = numberOfCases
+		}
+	}
+	if len(req) > 6 {
+		if req[6] != "" {
+			purchaseOrderTotalAmount, err := parseAmount(req[6])
+			if err != nil {
+				return err
+			}
+			s.PurchaseOrderTotalAmount = purchaseOrderTotalAmount
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard943V1s = []Standard943V1{
+		Standard943V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard943V1Transaction{
+				ShipmentNumber:        "987",
+				ShipmentDate:          "20210226",
+				ShipmentTime:          "132556",
+				ExpectedArrivalDate:   "20210305",
+				VendorID:              "707738",
+				DistributionCenterID:  "05",
+				ShipFromCompanyName:   "Overlook Hotel",
+				ShipFromAddress1:      "333 E Wonderview Ave",
+				ShipFromCityName:      "Estes Park",
+				ShipFromStateCode:     "CO",
+				ShipFromPostalCode:    "80517",
+				BOLNumber:             "33996",
+				CarrierRoutingDetails: "FEDL",
+			},
+			Pallets: []Standard943V1Pallet{
+				Standard943V1Pallet{
+					PalletID: "123456789123456789",
+					Shipments: []Standard943V1Shipment{
+						Standard943V1Shipment{
+							CarrierTrackingNumber:         "986979879878",
+							ManufacturersSerialCaseNumber: "345345",
+							BuyersPurchaseOrderNumber:     "34534534",
+							LineItems: []Standard943V1LineItem{
+								Standard943V1LineItem{
+									ItemIdentificationGTIN: "00821780002660",
+									MasterStyle:            "345345",
+									ColorCode:              "345345",
+									SizeCode:               "345345",
+									QuantityExpected:       12,
+									CountryOfOrigin:        "US",
+								},
+								Standard943V1LineItem{
+									ItemIdentificationGTIN: "00821780002799",
+									MasterStyle:            "345345",
+									ColorCode:              "345345",
+									SizeCode:               "345345",
+									QuantityExpected:       6,
+									CountryOfOrigin:        "US",
+								},
+							},
+						},
+					},
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard943V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard943V1Key, Standard943V1 := range Standard943V1s {
+		byteArrayPointer, err := Standard943V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/943-"+strconv.Itoa(Standard943V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, Standard943V1.Trailer.TotalCaseCount)
+		assert.Equal(t, 18, Standard943V1.Trailer.TotalQuantityExpected)
+		assert.Equal(t, 2, Standard943V1.Trailer.RecordCount)
+	}
+
+}
+
+func TestStandard943V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/943-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard943V1 Standard943V1
+	err := standard943V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "20210305", standard943V1.Transaction.ExpectedArrivalDate)
+	if assert.Len(t, standard943V1.Pallets, 1) && assert.Len(t, standard943V1.Pallets[0].Shipments, 1) {
+		lineItems := standard943V1.Pallets[0].Shipments[0].LineItems
+		if assert.Len(t, lineItems, 2) {
+			assert.Equal(t, "00821780002799", lineItems[1].ItemIdentificationGTIN)
+			assert.Equal(t, 6, lineItems[1].QuantityExpected)
+		}
+	}
+	assert.Equal(t, 18, standard943V1.Trailer.TotalQuantityExpected)
+
+}
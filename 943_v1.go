@@ -0,0 +1,529 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+type Standard943V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard943V1Transaction
+	Pallets           []Standard943V1Pallet
+	Trailer           Standard943V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard943V1Transaction struct {
+	Header                string
+	TransactionType       string
+	TransactionSetPurpose string
+	VersionNumber         string
+	ShipmentNumber        string
+	ShipmentDate          string
+	ShipmentTime          string
+	ExpectedArrivalDate   string
+	VendorID              string
+	DistributionCenter    string
+	DistributionCenterID  string
+	ShipFromCompanyName   string
+	ShipFromAddress1      string
+	ShipFromAddress2      string
+	ShipFromCityName      string
+	ShipFromStateCode     string
+	ShipFromPostalCode    string
+	ShipFromCountryCode   string
+	BOLNumber             string
+	CarrierRoutingDetails string
+	CarrierTrackingNumber string
+	TrailerID             string
+	SpecialInstructions   string
+}
+
+type Standard943V1Pallet struct {
+	PalletRecord string
+	PalletID     string
+	Shipments    []Standard943V1Shipment `csv:"-"`
+}
+
+type Standard943V1Shipment struct {
+	DetailSectionLoopA            string
+	CarrierTrackingNumber         string
+	ManufacturersSerialCaseNumber string
+	BuyersPurchaseOrderNumber     string
+	ManufacturersOrderNumber      string
+	LineItems                     []Standard943V1LineItem `csv:"-"`
+}
+
+type Standard943V1LineItem struct {
+	DetailSectionLoopB            string
+	LineItemNumber                int
+	ItemIdentificationGTIN        string
+	MasterStyle                   string
+	ColorCode                     string
+	SizeCode                      string
+	UnitOrBasisForMeasurementCode string
+	QuantityExpected              int
+	CountryOfOrigin               string
+	ManufacturersLotID            string
+}
+
+type Standard943V1Trailer struct {
+	TrailerRecord         string
+	TotalCaseCount        int
+	TotalQuantityExpected int
+	RecordCount           int
+	TotalPalletCount      int
+}
+
+func (s *Standard943V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "943"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "943"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	if s.Transaction.ShipmentDate == "" {
+		s.Transaction.ShipmentDate = time.Now().Format("20060102")
+		s.Transaction.ShipmentTime = time.Now().Format("150405")
+	}
+
+	// Pallets
+	var totalCaseCount, totalQuantityExpected, recordCount int
+	for palletKey, pallet := range s.Pallets {
+		s.Pallets[palletKey].PalletRecord = "05"
+
+		// Shipments
+		for palletShipmentKey, palletShipment := range pallet.Shipments {
+			s.Pallets[palletKey].Shipments[palletShipmentKey].DetailSectionLoopA = "02"
+			totalCaseCount++
+
+			// Line Items
+			for palletShipmentLineItemKey, lineItem := range palletShipment.LineItems {
+				recordCount++
+				s.Pallets[palletKey].Shipments[palletShipmentKey].LineItems[palletShipmentLineItemKey].DetailSectionLoopB = "03"
+				s.Pallets[palletKey].Shipments[palletShipmentKey].LineItems[palletShipmentLineItemKey].LineItemNumber = recordCount
+				if lineItem.UnitOrBasisForMeasurementCode == "" {
+					s.Pallets[palletKey].Shipments[palletShipmentKey].LineItems[palletShipmentLineItemKey].UnitOrBasisForMeasurementCode = "EA"
+				}
+				totalQuantityExpected += lineItem.QuantityExpected
+			}
+
+		}
+
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.TotalCaseCount = totalCaseCount
+	s.Trailer.TotalQuantityExpected = totalQuantityExpected
+	s.Trailer.RecordCount = recordCount
+	s.Trailer.TotalPalletCount = len(s.Pallets)
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard943V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Pallets
+	for _, pallet := range s.Pallets {
+		errPallet := enc.Encode(pallet)
+		if errPallet != nil {
+			return nil, errPallet
+		}
+
+		// Shipments
+		for _, shipment := range pallet.Shipments {
+			errShipment := enc.Encode(shipment)
+			if errShipment != nil {
+				return nil, errShipment
+			}
+
+			// Line Items
+			for _, lineItem := range shipment.LineItems {
+				errLineItem := enc.Encode(lineItem)
+				if errLineItem != nil {
+					return nil, errLineItem
+				}
+			}
+
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard943V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	var palletCount, shipmentCount int
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard943V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "05":
+			var x Standard943V1Pallet
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Pallets = append(s.Pallets, x)
+			palletCount++
+			shipmentCount = 0
+		case "02":
+			var x Standard943V1Shipment
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			if palletCount <= 0 {
+				s.Pallets = append(s.Pallets, Standard943V1Pallet{})
+				palletCount++
+			}
+			s.Pallets[palletCount-1].Shipments = append(s.Pallets[palletCount-1].Shipments, x)
+			shipmentCount++
+		case "03":
+			var x Standard943V1LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			if palletCount <= 0 {
+				s.Pallets = append(s.Pallets, Standard943V1Pallet{})
+				palletCount++
+			}
+			if shipmentCount <= 0 {
+				s.Pallets[palletCount-1].Shipments = append(s.Pallets[palletCount-1].Shipments, Standard943V1Shipment{})
+				shipmentCount++
+			}
+			s.Pallets[palletCount-1].Shipments[shipmentCount-1].LineItems = append(s.Pallets[palletCount-1].Shipments[shipmentCount-1].LineItems, x)
+		case "09":
+			var x Standard943V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+func (s *Standard943V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.ShipmentNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.ShipmentDate = req[5]
+	}
+	if len(req) > 6 {
+		s.ShipmentTime = req[6]
+	}
+	if len(req) > 7 {
+		s.ExpectedArrivalDate = req[7]
+	}
+	if len(req) > 8 {
+		s.VendorID = req[8]
+	}
+	if len(req) > 9 {
+		s.DistributionCenter = req[9]
+	}
+	if len(req) > 10 {
+		s.DistributionCenterID = req[10]
+	}
+	if len(req) > 11 {
+		s.ShipFromCompanyName = req[11]
+	}
+	if len(req) > 12 {
+		s.ShipFromAddress1 = req[12]
+	}
+	if len(req) > 13 {
+		s.ShipFromAddress2 = req[13]
+	}
+	if len(req) > 14 {
+		s.ShipFromCityName = req[14]
+	}
+	if len(req) > 15 {
+		s.ShipFromStateCode = req[15]
+	}
+	if len(req) > 16 {
+		s.ShipFromPostalCode = req[16]
+	}
+	if len(req) > 17 {
+		s.ShipFromCountryCode = req[17]
+	}
+	if len(req) > 18 {
+		s.BOLNumber = req[18]
+	}
+	if len(req) > 19 {
+		s.CarrierRoutingDetails = req[19]
+	}
+	if len(req) > 20 {
+		s.CarrierTrackingNumber = req[20]
+	}
+	if len(req) > 21 {
+		s.TrailerID = req[21]
+	}
+	if len(req) > 22 {
+		s.SpecialInstructions = req[22]
+	}
+
+	return nil
+}
+
+func (s *Standard943V1Pallet) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.PalletRecord = req[0]
+	}
+	if len(req) > 1 {
+		s.PalletID = req[1]
+	}
+
+	return nil
+}
+
+func (s *Standard943V1Shipment) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		s.CarrierTrackingNumber = req[1]
+	}
+	if len(req) > 2 {
+		s.ManufacturersSerialCaseNumber = req[2]
+	}
+	if len(req) > 3 {
+		s.BuyersPurchaseOrderNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.ManufacturersOrderNumber = req[4]
+	}
+
+	return nil
+}
+
+func (s *Standard943V1LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopB = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.ItemIdentificationGTIN = req[2]
+	}
+	if len(req) > 3 {
+		s.MasterStyle = req[3]
+	}
+	if len(req) > 4 {
+		s.ColorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.SizeCode = req[5]
+	}
+	if len(req) > 6 {
+		s.UnitOrBasisForMeasurementCode = req[6]
+	}
+	if len(req) > 7 {
+		if req[7] != "" {
+			quantityExpected, err := strconv.Atoi(req[7])
+			if err != nil {
+				return err
+			}
+			s.QuantityExpected = quantityExpected
+		}
+	}
+	if len(req) > 8 {
+		s.CountryOfOrigin = req[8]
+	}
+	if len(req) > 9 {
+		s.ManufacturersLotID = req[9]
+	}
+
+	return nil
+}
+
+func (s *Standard943V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			totalCaseCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.TotalCaseCount = totalCaseCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			totalQuantityExpected, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.TotalQuantityExpected = totalQuantityExpected
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			recordCount, err := strconv.Atoi(req[3])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 4 {
+		if req[4] != "" {
+			totalPalletCount, err := strconv.Atoi(req[4])
+			if err != nil {
+				return err
+			}
+			s.TotalPalletCount = totalPalletCount
+		}
+	}
+
+	return nil
+}
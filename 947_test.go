@@ -0,0 +1,126 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	Standard947V1s = []Standard947V1{
+		Standard947V1{
+			EnvelopeHeaderV3: EnvelopeHeaderV3{
+				InterchangeID: "202102268484912",
+				ReceiverID:    "123456789",
+				SenderID:      "383601069",
+			},
+			Transaction: Standard947V1Transaction{
+				AdjustmentNumber:     "ADJ-1001",
+				VendorID:             "707738",
+				DistributionCenter:   "Charlotte",
+				DistributionCenterID: "05",
+			},
+			LineItems: []Standard947V1LineItem{
+				Standard947V1LineItem{
+					ItemIdentificationGTIN: "00821780002660",
+					AdjustmentReasonCode:   Standard947V1ReasonPhysicalCount,
+					QuantityAdjusted:       4,
+				},
+				Standard947V1LineItem{
+					ItemIdentificationGTIN: "00821780002799",
+					AdjustmentReasonCode:   Standard947V1ReasonDamagedInFacility,
+					QuantityAdjusted:       -2,
+					AdjustmentDescription:  "Water damage",
+				},
+			},
+			EnvelopeTrailerV3: EnvelopeTrailerV3{
+				InterchangeID: "202102268484912",
+			},
+		},
+	}
+)
+
+func TestStandard947V1ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	for Standard947V1Key, Standard947V1 := range Standard947V1s {
+		byteArrayPointer, err := Standard947V1.ToBytes(ctx)
+		if byteArrayPointer != nil {
+			byteArray := *byteArrayPointer
+			err := ioutil.WriteFile("./examples/947-"+strconv.Itoa(Standard947V1Key)+".txt", byteArray, 0644)
+			if err != nil {
+				assert.Nil(t, err)
+			}
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, Standard947V1.Trailer.RecordCount)
+		assert.Equal(t, 2, Standard947V1.Trailer.NetQuantityAdjusted)
+	}
+
+}
+
+func TestStandard947V1FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/947-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard947V1 Standard947V1
+	err := standard947V1.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	if assert.Len(t, standard947V1.LineItems, 2) {
+		assert.Equal(t, -2, standard947V1.LineItems[1].QuantityAdjusted)
+		assert.Equal(t, Standard947V1ReasonDamagedInFacility, standard947V1.LineItems[1].AdjustmentReasonCode)
+	}
+
+}
+
+func TestStandard947V1Apply(t *testing.T) {
+
+	ctx := context.Background()
+
+	inventory := Standard846V3{
+		Sections: []Standard846V3Section{
+			Standard846V3Section{
+				Header: Standard846V3TransactionHeader{DistributionCenterID: "01"},
+				LineItems: []Standard846V3LineItem{
+					Standard846V3LineItem{ItemIdentificationGTIN: "00821780002660", CurrentInventoryLevel: 100},
+				},
+			},
+			Standard846V3Section{
+				Header: Standard846V3TransactionHeader{DistributionCenterID: "05"},
+				LineItems: []Standard846V3LineItem{
+					Standard846V3LineItem{ItemIdentificationGTIN: "00821780002799", CurrentInventoryLevel: 10},
+				},
+			},
+		},
+	}
+
+	adjusted, err := Standard947V1s[0].Apply(ctx, inventory)
+	assert.Nil(t, err)
+	if assert.Len(t, adjusted.Sections, 2) && assert.Len(t, adjusted.Sections[1].LineItems, 2) {
+		assert.Equal(t, 100, adjusted.Sections[0].LineItems[0].CurrentInventoryLevel)
+		assert.Equal(t, 8, adjusted.Sections[1].LineItems[0].CurrentInventoryLevel)
+		assert.Equal(t, "00821780002660", adjusted.Sections[1].LineItems[1].ItemIdentificationGTIN)
+		assert.Equal(t, 4, adjusted.Sections[1].LineItems[1].CurrentInventoryLevel)
+		assert.Equal(t, 2, adjusted.Sections[1].Trailer.RecordCount)
+		assert.Equal(t, 2, adjusted.Trailer.RecordCount)
+	}
+	assert.Len(t, adjusted.LineItems, 3)
+	assert.Equal(t, 10, inventory.Sections[1].LineItems[0].CurrentInventoryLevel)
+
+	// Negative
+	inventory.Sections[1].LineItems[0].CurrentInventoryLevel = 1
+	_, err = Standard947V1s[0].Apply(ctx, inventory)
+	assert.NotNil(t, err)
+
+}
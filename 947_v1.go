@@ -0,0 +1,431 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard947V1ReasonPhysicalCount     = "AA"
+	Standard947V1ReasonDamagedInFacility = "AB"
+	Standard947V1ReasonDamagedInTransit  = "AC"
+	Standard947V1ReasonProductRecall     = "AD"
+)
+
+// Standard947V1Reasons lists the adjustment reason codes.
+var Standard947V1Reasons = []string{Standard947V1ReasonPhysicalCount, Standard947V1ReasonDamagedInFacility, Standard947V1ReasonDamagedInTransit, Standard947V1ReasonProductRecall}
+
+type Standard947V1 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Transaction       Standard947V1Transaction
+	LineItems         []Standard947V1LineItem
+	Trailer           Standard947V1Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard947V1Transaction struct {
+	Header                string
+	TransactionType       string
+	TransactionSetPurpose string
+	VersionNumber         string
+	AdjustmentNumber      string
+	AdjustmentDate        string
+	AdjustmentTime        string
+	VendorID              string
+	DistributionCenter    string
+	DistributionCenterID  string
+}
+
+type Standard947V1LineItem struct {
+	DetailSectionLoopA     string
+	LineItemNumber         int
+	ItemIdentificationGTIN string
+	MasterStyle            string
+	ColorCode              string
+	SizeCode               string
+	UnitOfMeasure          string
+	AdjustmentReasonCode   string
+	QuantityAdjusted       int
+	AdjustmentDescription  string
+}
+
+type Standard947V1Trailer struct {
+	TrailerRecord       string
+	RecordCount         int
+	NetQuantityAdjusted int
+}
+
+func (s *Standard947V1) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "947"
+
+	// Transaction
+	s.Transaction.Header = "01"
+	s.Transaction.TransactionType = "947"
+	if s.Transaction.TransactionSetPurpose == "" {
+		s.Transaction.TransactionSetPurpose = "00"
+	}
+	s.Transaction.VersionNumber = "1.0"
+	if s.Transaction.AdjustmentDate == "" {
+		s.Transaction.AdjustmentDate = time.Now().Format("20060102")
+		s.Transaction.AdjustmentTime = time.Now().Format("150405")
+	}
+
+	// Line Items
+	var netQuantityAdjusted int
+	for lineItemKey, lineItem := range s.LineItems {
+		s.LineItems[lineItemKey].DetailSectionLoopA = "02"
+		s.LineItems[lineItemKey].LineItemNumber = lineItemKey + 1
+		if lineItem.UnitOfMeasure == "" {
+			s.LineItems[lineItemKey].UnitOfMeasure = "EA"
+		}
+		netQuantityAdjusted += lineItem.QuantityAdjusted
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.LineItems)
+	s.Trailer.NetQuantityAdjusted = netQuantityAdjusted
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard947V1) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Transaction
+	errTransaction := enc.Encode(s.Transaction)
+	if errTransaction != nil {
+		return nil, errTransaction
+	}
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		errLineItem := enc.Encode(lineItem)
+		if errLineItem != nil {
+			return nil, errLineItem
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard947V1) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard947V1Transaction
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Transaction = x
+		case "02":
+			var x Standard947V1LineItem
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.LineItems = append(s.LineItems, x)
+		case "09":
+			var x Standard947V1Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// Apply returns a copy of the inventory snapshot with the adjustments added
+// to the section of the matching distribution center. Items missing from the
+// snapshot are appended and the section trailer counts them.
+func (s *Standard947V1) Apply(ctx context.Context, inventory Standard846V3) (Standard846V3, error) {
+
+	adjusted := inventory
+
+	// Sections
+	adjusted.Sections = make([]Standard846V3Section, len(inventory.Sections))
+	for sectionKey, section := range inventory.Sections {
+		adjusted.Sections[sectionKey] = section
+		adjusted.Sections[sectionKey].LineItems = append([]Standard846V3LineItem(nil), section.LineItems...)
+	}
+	if len(adjusted.Sections) == 0 {
+		adjusted.Sections = append(adjusted.Sections, Standard846V3Section{
+			Header:    inventory.Header,
+			LineItems: append([]Standard846V3LineItem(nil), inventory.LineItems...),
+			Trailer:   inventory.Trailer,
+		})
+	}
+
+	// Distribution Center
+	sectionKey := -1
+	for key, section := range adjusted.Sections {
+		if s.Transaction.DistributionCenterID == "" || section.Header.DistributionCenterID == s.Transaction.DistributionCenterID {
+			if sectionKey >= 0 {
+				return inventory, fmt.Errorf("947 %s matches more than one distribution center", s.Transaction.AdjustmentNumber)
+			}
+			sectionKey = key
+		}
+	}
+	if sectionKey < 0 {
+		return inventory, fmt.Errorf("846 has no section for distribution center %s", s.Transaction.DistributionCenterID)
+	}
+
+	// Line Items
+	lineItems := adjusted.Sections[sectionKey].LineItems
+	for _, adjustment := range s.LineItems {
+		lineItemKey := -1
+		for key, lineItem := range lineItems {
+			if lineItem.ItemIdentificationGTIN == adjustment.ItemIdentificationGTIN {
+				lineItemKey = key
+				break
+			}
+		}
+		if lineItemKey < 0 {
+			lineItems = append(lineItems, Standard846V3LineItem{
+				DetailSectionLoopA:     "02",
+				LineItemNumber:         len(lineItems) + 1,
+				ItemIdentificationGTIN: adjustment.ItemIdentificationGTIN,
+				UnitOfMeasure:          adjustment.UnitOfMeasure,
+			})
+			lineItemKey = len(lineItems) - 1
+		}
+		lineItems[lineItemKey].CurrentInventoryLevel += adjustment.QuantityAdjusted
+		if lineItems[lineItemKey].CurrentInventoryLevel < 0 {
+			return inventory, fmt.Errorf("adjustment of %d leaves negative inventory for GTIN %s", adjustment.QuantityAdjusted, adjustment.ItemIdentificationGTIN)
+		}
+	}
+	adjusted.Sections[sectionKey].LineItems = lineItems
+
+	// Trailer
+	adjusted.Sections[sectionKey].Trailer.RecordCount = len(lineItems)
+
+	// Flat Line Items
+	adjusted.LineItems = nil
+	for _, section := range adjusted.Sections {
+		adjusted.LineItems = append(adjusted.LineItems, section.LineItems...)
+	}
+	adjusted.Trailer = adjusted.Sections[len(adjusted.Sections)-1].Trailer
+
+	return adjusted, nil
+}
+
+func (s *Standard947V1Transaction) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.TransactionSetPurpose = req[2]
+	}
+	if len(req) > 3 {
+		s.VersionNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.AdjustmentNumber = req[4]
+	}
+	if len(req) > 5 {
+		s.AdjustmentDate = req[5]
+	}
+	if len(req) > 6 {
+		s.AdjustmentTime = req[6]
+	}
+	if len(req) > 7 {
+		s.VendorID = req[7]
+	}
+	if len(req) > 8 {
+		s.DistributionCenter = req[8]
+	}
+	if len(req) > 9 {
+		s.DistributionCenterID = req[9]
+	}
+
+	return nil
+}
+
+func (s *Standard947V1LineItem) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.ItemIdentificationGTIN = req[2]
+	}
+	if len(req) > 3 {
+		s.MasterStyle = req[3]
+	}
+	if len(req) > 4 {
+		s.ColorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.SizeCode = req[5]
+	}
+	if len(req) > 6 {
+		s.UnitOfMeasure = req[6]
+	}
+	if len(req) > 7 {
+		s.AdjustmentReasonCode = req[7]
+	}
+	if len(req) > 8 {
+		if req[8] != "" {
+			quantityAdjusted, err := strconv.Atoi(req[8])
+			if err != nil {
+				return err
+			}
+			s.QuantityAdjusted = quantityAdjusted
+		}
+	}
+	if len(req) > 9 {
+		s.AdjustmentDescription = req[9]
+	}
+
+	return nil
+}
+
+func (s *Standard947V1Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			netQuantityAdjusted, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.NetQuantityAdjusted = netQuantityAdjusted
+		}
+	}
+
+	return nil
+}
@@ -83,4 +83,80 @@ func TestStandard997V2FromBytes(t *testing.T) {
 	err := Standard997V2.FromBytes(ctx, bytes)
 	assert.Nil(t, err)
 	
-}
\ No newline at end of file
+}
+
+func TestStandard997V3ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	var standard852V1 Standard852V1
+	errParse := standard852V1.FromBytes(ctx, []byte("01\t852\t00\t1.0\tW08\n05\t8976\n02\t1\t00707738003265\t2002\tNAV\tS\tEA\tfour\n"))
+	assert.NotNil(t, errParse)
+
+	var malformed Standard852V1
+	errCSV := malformed.FromBytes(ctx, []byte("01\t852\n02\t1\"\n"))
+	assert.NotNil(t, errCSV)
+
+	rejected := ValidationReport{TransactionType: "852", PurchaseOrderNumber: "W08"}
+	rejected.AddParseError(ctx, errParse)
+	rejected.AddParseError(ctx, errCSV)
+
+	standard997V3 := Standard997V3{
+		EnvelopeHeaderV3: EnvelopeHeaderV3{
+			InterchangeID: "202102268484912",
+			ReceiverID:    "123456789",
+			SenderID:      "383601069",
+		},
+		Body: Standard997V3Body{
+			SenderID:         "014628093",
+			ProductionOrTest: "T",
+			InterchangeID:    "123456789",
+		},
+		Documents: make([]Standard997V3Document, 3),
+		EnvelopeTrailerV3: EnvelopeTrailerV3{
+			InterchangeID: "202102268484912",
+		},
+	}
+	assert.Nil(t, standard997V3.Documents[0].FromValidationReport(ctx, ValidationReport{TransactionType: "850", PurchaseOrderNumber: "12345678"}))
+	assert.Nil(t, standard997V3.Documents[1].FromValidationReport(ctx, rejected))
+	assert.Nil(t, standard997V3.Documents[2].FromValidationReport(ctx, ValidationReport{TransactionType: "180", PurchaseOrderNumber: "12345678"}))
+
+	byteArrayPointer, err := standard997V3.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer != nil {
+		err := ioutil.WriteFile("./examples/997v3-0.txt", *byteArrayPointer, 0644)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, Standard997V3PartiallyAccepted, standard997V3.Body.TransactionSetAcknowledgementCodes)
+	assert.Equal(t, Standard997V3Rejected, standard997V3.Documents[1].TransactionSetAcknowledgementCode)
+	assert.Equal(t, 2, standard997V3.Documents[1].ErrorCount)
+	assert.Equal(t, 2, standard997V3.Trailer.AcceptedDocumentCount)
+	assert.Equal(t, 1, standard997V3.Trailer.RejectedDocumentCount)
+
+}
+
+func TestStandard997V3FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/997v3-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard997V3 Standard997V3
+	err := standard997V3.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, Standard997V3PartiallyAccepted, standard997V3.Body.TransactionSetAcknowledgementCodes)
+	if assert.Len(t, standard997V3.Documents, 3) && assert.Len(t, standard997V3.Documents[1].Errors, 2) {
+		assert.Equal(t, "W08", standard997V3.Documents[1].PurchaseOrderNumber)
+		assert.Equal(t, ValidationCodeParse, standard997V3.Documents[1].Errors[0].ErrorCode)
+		assert.Equal(t, 2, standard997V3.Documents[1].Errors[1].Line)
+		assert.Equal(t, 5, standard997V3.Documents[1].Errors[1].Column)
+		assert.Empty(t, standard997V3.Documents[0].Errors)
+	}
+	assert.Equal(t, 3, standard997V3.Trailer.RecordCount)
+
+}
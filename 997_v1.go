@@ -43,7 +43,9 @@ func (s *Standard997V1) Prep(ctx context.Context) (error){
 	s.Body.Header = "01"
 	s.Body.TransactionType = "997"
 	s.Body.VersionNumber = "2.0"
-	s.Body.SenderQualifier = "01"
+	if s.Body.SenderQualifier == "" {
+		s.Body.SenderQualifier = "01"
+	}
 	s.Body.FileCreationDate = time.Now().Format("20060102")
 	s.Body.FileCreationTime = time.Now().Format("150405")
 
@@ -108,6 +110,7 @@ func (s *Standard997V1) FromBytes(ctx context.Context, req []byte) (error){
 
 	r := csv.NewReader(bytes.NewReader(req))
 	r.Comma = '\t'
+	r.FieldsPerRecord = -1
 
 	// Headerless
 	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
@@ -123,9 +126,13 @@ func (s *Standard997V1) FromBytes(ctx context.Context, req []byte) (error){
 
 	for {
 		var v struct{}
-		if err := dec.Decode(&v); err == io.EOF {
+		err := dec.Decode(&v)
+		if err == io.EOF {
 			break
 		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
 
 		// Record
 		var lineType string
@@ -0,0 +1,493 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jszwec/csvutil"
+)
+
+const (
+	Standard997V3Accepted           = "A"
+	Standard997V3AcceptedWithErrors = "E"
+	Standard997V3PartiallyAccepted  = "P"
+	Standard997V3Rejected           = "R"
+)
+
+// Standard997V3AcknowledgementCodes lists the acknowledgement codes.
+var Standard997V3AcknowledgementCodes = []string{Standard997V3Accepted, Standard997V3AcceptedWithErrors, Standard997V3PartiallyAccepted, Standard997V3Rejected}
+
+type Standard997V3 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Body              Standard997V3Body
+	Documents         []Standard997V3Document
+	Trailer           Standard997V3Trailer
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+}
+
+type Standard997V3Body struct {
+	Header                             string
+	TransactionType                    string
+	VersionNumber                      string
+	SenderQualifier                    string
+	SenderID                           string
+	ReceiverQualifier                  string
+	ReceiverID                         string
+	FileCreationDate                   string
+	FileCreationTime                   string
+	ProductionOrTest                   string
+	InterchangeID                      string
+	TransactionSetAcknowledgementCodes string
+}
+
+type Standard997V3Document struct {
+	DetailSectionLoopA                string
+	LineItemNumber                    int
+	TransactionType                   string
+	PurchaseOrderNumber               string
+	TransactionSetAcknowledgementCode string
+	ErrorCount                        int
+	Errors                            []Standard997V3Error `csv:"-"`
+}
+
+type Standard997V3Error struct {
+	DetailSectionLoopB   string
+	RecordType           string
+	RecordLineItemNumber int
+	FieldName            string
+	ErrorCode            string
+	ErrorMessage         string
+	Line                 int
+	Column               int
+}
+
+type Standard997V3Trailer struct {
+	TrailerRecord         string
+	RecordCount           int
+	AcceptedDocumentCount int
+	RejectedDocumentCount int
+}
+
+// Prep acknowledges a document without an explicit code as accepted when it
+// has no errors and rejected otherwise. The interchange is accepted when every
+// document is, rejected when none is, and partially accepted in between.
+func (s *Standard997V3) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+	s.EnvelopeHeaderV3.TransactionType = "997"
+
+	// Transaction
+	s.Body.Header = "01"
+	s.Body.TransactionType = "997"
+	s.Body.VersionNumber = "3.0"
+	if s.Body.SenderQualifier == "" {
+		s.Body.SenderQualifier = "01"
+	}
+	s.Body.FileCreationDate = time.Now().Format("20060102")
+	s.Body.FileCreationTime = time.Now().Format("150405")
+
+	// Documents
+	var acceptedDocumentCount, rejectedDocumentCount int
+	for documentKey, document := range s.Documents {
+		s.Documents[documentKey].DetailSectionLoopA = "02"
+		s.Documents[documentKey].LineItemNumber = documentKey + 1
+		s.Documents[documentKey].ErrorCount = len(document.Errors)
+		if document.TransactionSetAcknowledgementCode == "" {
+			document.TransactionSetAcknowledgementCode = Standard997V3Accepted
+			if len(document.Errors) > 0 {
+				document.TransactionSetAcknowledgementCode = Standard997V3Rejected
+			}
+			s.Documents[documentKey].TransactionSetAcknowledgementCode = document.TransactionSetAcknowledgementCode
+		}
+		if document.TransactionSetAcknowledgementCode == Standard997V3Rejected {
+			rejectedDocumentCount++
+		} else {
+			acceptedDocumentCount++
+		}
+
+		// Errors
+		for errorKey := range document.Errors {
+			s.Documents[documentKey].Errors[errorKey].DetailSectionLoopB = "03"
+		}
+	}
+	if len(s.Documents) > 0 {
+		switch {
+		case rejectedDocumentCount == 0:
+			s.Body.TransactionSetAcknowledgementCodes = Standard997V3Accepted
+		case acceptedDocumentCount == 0:
+			s.Body.TransactionSetAcknowledgementCodes = Standard997V3Rejected
+		default:
+			s.Body.TransactionSetAcknowledgementCodes = Standard997V3PartiallyAccepted
+		}
+	}
+
+	// Trailer
+	s.Trailer.TrailerRecord = "09"
+	s.Trailer.RecordCount = len(s.Documents)
+	s.Trailer.AcceptedDocumentCount = acceptedDocumentCount
+	s.Trailer.RejectedDocumentCount = rejectedDocumentCount
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.Prep(ctx)
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *Standard997V3) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Envelope Header
+	errEnvelopeHeaderV3 := enc.Encode(s.EnvelopeHeaderV3)
+	if errEnvelopeHeaderV3 != nil {
+		return nil, errEnvelopeHeaderV3
+	}
+
+	// Body
+	errBody := enc.Encode(s.Body)
+	if errBody != nil {
+		return nil, errBody
+	}
+
+	// Documents
+	for _, document := range s.Documents {
+		errDocument := enc.Encode(document)
+		if errDocument != nil {
+			return nil, errDocument
+		}
+
+		// Errors
+		for _, documentError := range document.Errors {
+			errDocumentError := enc.Encode(documentError)
+			if errDocumentError != nil {
+				return nil, errDocumentError
+			}
+		}
+	}
+
+	// Trailer
+	errTrailer := enc.Encode(s.Trailer)
+	if errTrailer != nil {
+		return nil, errTrailer
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailerV3 := enc.Encode(s.EnvelopeTrailerV3)
+	if errEnvelopeTrailerV3 != nil {
+		return nil, errEnvelopeTrailerV3
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
+
+func (s *Standard997V3) FromBytes(ctx context.Context, req []byte) error {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	// Headerless
+	blankHeader, errHeader := csvutil.Header(Header{}, "csv")
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Decoder
+	dec, errDecoder := csvutil.NewDecoder(r, blankHeader...)
+	if errDecoder != nil {
+		return errDecoder
+	}
+
+	var documentCount int
+	for {
+		var v struct{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != csvutil.ErrFieldCount {
+			return err
+		}
+
+		// Record
+		var lineType string
+		record := dec.Record()
+		if len(record) > 0 {
+			lineType = record[0]
+		}
+
+		// Build
+		switch lineType {
+		case "EASI":
+			var x EnvelopeHeaderV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeHeaderV3 = x
+		case "01":
+			var x Standard997V3Body
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Body = x
+		case "02":
+			var x Standard997V3Document
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Documents = append(s.Documents, x)
+			documentCount++
+		case "03":
+			var x Standard997V3Error
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			if documentCount <= 0 {
+				s.Documents = append(s.Documents, Standard997V3Document{})
+				documentCount++
+			}
+			s.Documents[documentCount-1].Errors = append(s.Documents[documentCount-1].Errors, x)
+		case "09":
+			var x Standard997V3Trailer
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.Trailer = x
+		case "EASX":
+			var x EnvelopeTrailerV3
+			err := x.FromSlice(ctx, record)
+			if err != nil {
+				return err
+			}
+			s.EnvelopeTrailerV3 = x
+		default:
+
+		}
+
+	}
+
+	return nil
+}
+
+// FromValidationReport acknowledges one document from its validation report,
+// which may also hold the errors returned while parsing it.
+func (s *Standard997V3Document) FromValidationReport(ctx context.Context, report ValidationReport) error {
+
+	s.TransactionType = report.TransactionType
+	s.PurchaseOrderNumber = report.PurchaseOrderNumber
+	s.TransactionSetAcknowledgementCode = Standard997V3Accepted
+	if !report.Valid() {
+		s.TransactionSetAcknowledgementCode = Standard997V3Rejected
+	}
+
+	// Errors
+	s.Errors = nil
+	for _, validationError := range report.Errors {
+		s.Errors = append(s.Errors, Standard997V3Error{
+			RecordType:           validationError.RecordType,
+			RecordLineItemNumber: validationError.LineItemNumber,
+			FieldName:            validationError.Field,
+			ErrorCode:            validationError.Code,
+			ErrorMessage:         validationError.Message,
+			Line:                 validationError.Line,
+			Column:               validationError.Column,
+		})
+	}
+
+	return nil
+}
+
+func (s *Standard997V3Body) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.Header = req[0]
+	}
+	if len(req) > 1 {
+		s.TransactionType = req[1]
+	}
+	if len(req) > 2 {
+		s.VersionNumber = req[2]
+	}
+	if len(req) > 3 {
+		s.SenderQualifier = req[3]
+	}
+	if len(req) > 4 {
+		s.SenderID = req[4]
+	}
+	if len(req) > 5 {
+		s.ReceiverQualifier = req[5]
+	}
+	if len(req) > 6 {
+		s.ReceiverID = req[6]
+	}
+	if len(req) > 7 {
+		s.FileCreationDate = req[7]
+	}
+	if len(req) > 8 {
+		s.FileCreationTime = req[8]
+	}
+	if len(req) > 9 {
+		s.ProductionOrTest = req[9]
+	}
+	if len(req) > 10 {
+		s.InterchangeID = req[10]
+	}
+	if len(req) > 11 {
+		s.TransactionSetAcknowledgementCodes = req[11]
+	}
+
+	return nil
+}
+
+func (s *Standard997V3Document) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopA = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			lineItemNumber, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.LineItemNumber = lineItemNumber
+		}
+	}
+	if len(req) > 2 {
+		s.TransactionType = req[2]
+	}
+	if len(req) > 3 {
+		s.PurchaseOrderNumber = req[3]
+	}
+	if len(req) > 4 {
+		s.TransactionSetAcknowledgementCode = req[4]
+	}
+	if len(req) > 5 {
+		if req[5] != "" {
+			errorCount, err := strconv.Atoi(req[5])
+			if err != nil {
+				return err
+			}
+			s.ErrorCount = errorCount
+		}
+	}
+
+	return nil
+}
+
+func (s *Standard997V3Error) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.DetailSectionLoopB = req[0]
+	}
+	if len(req) > 1 {
+		s.RecordType = req[1]
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			recordLineItemNumber, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.RecordLineItemNumber = recordLineItemNumber
+		}
+	}
+	if len(req) > 3 {
+		s.FieldName = req[3]
+	}
+	if len(req) > 4 {
+		s.ErrorCode = req[4]
+	}
+	if len(req) > 5 {
+		s.ErrorMessage = req[5]
+	}
+	if len(req) > 6 {
+		if req[6] != "" {
+			line, err := strconv.Atoi(req[6])
+			if err != nil {
+				return err
+			}
+			s.Line = line
+		}
+	}
+	if len(req) > 7 {
+		if req[7] != "" {
+			column, err := strconv.Atoi(req[7])
+			if err != nil {
+				return err
+			}
+			s.Column = column
+		}
+	}
+
+	return nil
+}
+
+func (s *Standard997V3Trailer) FromSlice(ctx context.Context, req []string) error {
+
+	if len(req) > 0 {
+		s.TrailerRecord = req[0]
+	}
+	if len(req) > 1 {
+		if req[1] != "" {
+			recordCount, err := strconv.Atoi(req[1])
+			if err != nil {
+				return err
+			}
+			s.RecordCount = recordCount
+		}
+	}
+	if len(req) > 2 {
+		if req[2] != "" {
+			acceptedDocumentCount, err := strconv.Atoi(req[2])
+			if err != nil {
+				return err
+			}
+			s.AcceptedDocumentCount = acceptedDocumentCount
+		}
+	}
+	if len(req) > 3 {
+		if req[3] != "" {
+			rejectedDocumentCount, err := strconv.Atoi(req[3])
+			if err != nil {
+				return err
+			}
+			s.RejectedDocumentCount = rejectedDocumentCount
+		}
+	}
+
+	return nil
+}
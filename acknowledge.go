@@ -0,0 +1,107 @@
+package easi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Acknowledge returns the 997 for a parsed inbound document: a *Standard997V1
+// for a document in an EASI 2.0 envelope and a *Standard997V2 for one in an
+// EASI 3.0 envelope. The 997 goes back from the receiver of the document to
+// its sender under the original InterchangeID, and accepts the document when
+// the report is valid. Errors returned while parsing belong in the report, as
+// added with AddParseError.
+func Acknowledge(ctx context.Context, document Document, report ValidationReport) (Document, error) {
+
+	var code string
+	if report.Valid() {
+		code = "A"
+	} else {
+		code = "R"
+	}
+
+	envelopeHeader, versionNumber, err := documentEnvelopeHeader(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// EASI 2.0
+	if versionNumber == "2.0" {
+		return &Standard997V1{
+			EnvelopeHeaderV2: EnvelopeHeaderV2{
+				SenderQualifier:   envelopeHeader.ReceiverQualifier,
+				SenderID:          envelopeHeader.ReceiverID,
+				ReceiverQualifier: envelopeHeader.SenderQualifier,
+				ReceiverID:        envelopeHeader.SenderID,
+				ProductionOrTest:  envelopeHeader.ProductionOrTest,
+				InterchangeID:     envelopeHeader.InterchangeID,
+			},
+			Body: Standard997V1Body{
+				SenderQualifier:                    envelopeHeader.ReceiverQualifier,
+				SenderID:                           envelopeHeader.ReceiverID,
+				ReceiverQualifier:                  envelopeHeader.SenderQualifier,
+				ReceiverID:                         envelopeHeader.SenderID,
+				ProductionOrTest:                   envelopeHeader.ProductionOrTest,
+				InterchangeID:                      envelopeHeader.InterchangeID,
+				TransactionSetAcknowledgementCodes: code,
+			},
+			EnvelopeTrailerV2: EnvelopeTrailerV2{
+				InterchangeID: envelopeHeader.InterchangeID,
+			},
+		}, nil
+	}
+
+	// EASI 3.0
+	return &Standard997V2{
+		EnvelopeHeaderV3: EnvelopeHeaderV3{
+			SenderQualifier:   envelopeHeader.ReceiverQualifier,
+			SenderID:          envelopeHeader.ReceiverID,
+			ReceiverQualifier: envelopeHeader.SenderQualifier,
+			ReceiverID:        envelopeHeader.SenderID,
+			ProductionOrTest:  envelopeHeader.ProductionOrTest,
+			InterchangeID:     envelopeHeader.InterchangeID,
+		},
+		Body: Standard997V2Body{
+			SenderQualifier:                    envelopeHeader.ReceiverQualifier,
+			SenderID:                           envelopeHeader.ReceiverID,
+			ReceiverQualifier:                  envelopeHeader.SenderQualifier,
+			ReceiverID:                         envelopeHeader.SenderID,
+			ProductionOrTest:                   envelopeHeader.ProductionOrTest,
+			InterchangeID:                      envelopeHeader.InterchangeID,
+			TransactionSetAcknowledgementCodes: code,
+		},
+		EnvelopeTrailerV3: EnvelopeTrailerV3{
+			InterchangeID: envelopeHeader.InterchangeID,
+		},
+	}, nil
+}
+
+// documentEnvelopeHeader returns the envelope header of a document, as an
+// EnvelopeHeaderV3 for either version, and the EASI version of the envelope.
+func documentEnvelopeHeader(document Document) (EnvelopeHeaderV3, string, error) {
+
+	documentValue := reflect.ValueOf(document)
+	if documentValue.Kind() == reflect.Ptr {
+		documentValue = documentValue.Elem()
+	}
+	if documentValue.Kind() != reflect.Struct {
+		return EnvelopeHeaderV3{}, "", fmt.Errorf("%T has no EASI envelope", document)
+	}
+
+	// EASI 2.0
+	if field := documentValue.FieldByName("EnvelopeHeaderV2"); field.IsValid() {
+		if envelopeHeaderV2, ok := field.Interface().(EnvelopeHeaderV2); ok {
+			return EnvelopeHeaderV3(envelopeHeaderV2), "2.0", nil
+		}
+	}
+
+	// EASI 3.0
+	if field := documentValue.FieldByName("EnvelopeHeaderV3"); field.IsValid() {
+		if envelopeHeaderV3, ok := field.Interface().(EnvelopeHeaderV3); ok {
+			return envelopeHeaderV3, "3.0", nil
+		}
+	}
+
+	return EnvelopeHeaderV3{}, "", fmt.Errorf("%T has no EASI envelope", document)
+}
@@ -0,0 +1,51 @@
+package easi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcknowledge(t *testing.T) {
+
+	ctx := context.Background()
+
+	// EASI 3.0
+	standard850V4 := Standard850V4s[0]
+	standard850V4.EnvelopeHeaderV3.SenderQualifier = "ZZ"
+	document, err := Acknowledge(ctx, &standard850V4, ValidationReport{})
+	assert.Nil(t, err)
+	if standard997V2, ok := document.(*Standard997V2); assert.True(t, ok) {
+		assert.Equal(t, standard850V4.EnvelopeHeaderV3.InterchangeID, standard997V2.Body.InterchangeID)
+		assert.Equal(t, standard850V4.EnvelopeHeaderV3.SenderID, standard997V2.EnvelopeHeaderV3.ReceiverID)
+		assert.Equal(t, standard850V4.EnvelopeHeaderV3.ReceiverID, standard997V2.Body.SenderID)
+		assert.Equal(t, "A", standard997V2.Body.TransactionSetAcknowledgementCodes)
+
+		_, err := standard997V2.ToBytes(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "ZZ", standard997V2.EnvelopeHeaderV3.ReceiverQualifier)
+		assert.Equal(t, "01", standard997V2.EnvelopeHeaderV3.SenderQualifier)
+	}
+
+	// EASI 2.0
+	var report ValidationReport
+	report.AddParseError(ctx, errors.New("unexpected record"))
+	document, err = Acknowledge(ctx, &standard850V1s[0], report)
+	assert.Nil(t, err)
+	if standard997V1, ok := document.(*Standard997V1); assert.True(t, ok) {
+		assert.Equal(t, standard850V1s[0].EnvelopeHeaderV2.InterchangeID, standard997V1.EnvelopeTrailerV2.InterchangeID)
+		assert.Equal(t, "R", standard997V1.Body.TransactionSetAcknowledgementCodes)
+	}
+
+	// Batch
+	document, err = Acknowledge(ctx, &Standard850V4Batch{}, report)
+	assert.Nil(t, err)
+	assert.IsType(t, &Standard997V2{}, document)
+
+	// No Envelope
+	_, err = Acknowledge(ctx, nil, report)
+	assert.NotNil(t, err)
+
+}
@@ -0,0 +1,799 @@
+package easi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The canonical types carry the business content of a document independently
+// of the EASI version it is exchanged in. Each maps onto the latest version of
+// its standard, and onto earlier versions through the version converters;
+// fields without a canonical counterpart are not carried, use the converters
+// directly for a report of those. Dates and times keep the EASI layouts
+// 20060102 and 150405, and amounts are in cents.
+
+type Address struct {
+	CompanyName  string
+	ContactName  string
+	Address1     string
+	Address2     string
+	CityName     string
+	StateCode    string
+	PostalCode   string
+	CountryCode  string
+	PhoneNumber  string
+	EmailAddress string
+}
+
+type PurchaseOrder struct {
+	Purpose               string
+	TypeCode              string
+	Number                string
+	ReleaseNumber         string
+	Date                  string
+	Time                  string
+	CurrencyCode          string
+	PurchaserAccountID    string
+	VendorID              string
+	StoreID               string
+	DistributionCenterID  string
+	RequestedShipDate     string
+	CancelDate            string
+	CarrierRoutingDetails string
+	DropShip              bool
+	ShipTo                Address
+	DeliveryInstructions  string
+	OrderInstructions     string
+	Lines                 []OrderLine
+	Charges               []Charge
+}
+
+// WarehouseOrder is a PurchaseOrder sent to a warehouse to be shipped, with
+// the parcel and COD details the warehouse ships it by and the order totals.
+type WarehouseOrder struct {
+	PurchaseOrder
+	ShipToCounty            string
+	ShipToSiteType          string
+	DeliveryServiceLevel    string
+	TrackingID              string
+	CustomerPONumber        string
+	PromotionalCode         string
+	AccountNumber           string
+	AccountName             string
+	PurchasersAccountID     string
+	ThirdPartyAccountNumber string
+	CODForMerchandise       string
+	CODTags                 string
+	NumberOfCases           int
+	TotalQuantity           int
+	LinesAmount             int
+	ChargesAmount           int
+	TotalAmount             int
+}
+
+type OrderLine struct {
+	LineNumber  int
+	GTIN        string
+	MasterStyle string
+	ColorCode   string
+	SizeCode    string
+	Quantity    int
+	UOM         string
+	UnitPrice   int
+}
+
+type Charge struct {
+	LineNumber  int
+	Description string
+	Amount      int
+}
+
+type ShipNotice struct {
+	Purpose               string
+	Number                string
+	Date                  string
+	Time                  string
+	ShipmentDate          string
+	VendorID              string
+	PurchaserAccountID    string
+	StoreID               string
+	DistributionCenterID  string
+	DropShip              bool
+	ShipTo                Address
+	BOLNumber             string
+	CarrierRoutingDetails string
+	TrailerID             string
+	Pallets               []ShipNoticePallet
+}
+
+type ShipNoticePallet struct {
+	ID      string
+	Cartons []ShipNoticeCarton
+}
+
+type ShipNoticeCarton struct {
+	CaseNumber            string
+	PurchaseOrderNumber   string
+	PurchaseOrderTypeCode string
+	PurchaseOrderDate     string
+	PurchaseOrderTime     string
+	OrderNumber           string
+	CarrierTrackingNumber string
+	TrackingID            string
+	Weight                float64
+	FreightCharge         int
+	Lines                 []ShipNoticeLine
+}
+
+type ShipNoticeLine struct {
+	LineNumber          int
+	GTIN                string
+	MasterStyle         string
+	DetailStyle         string
+	ColorCode           string
+	SizeCode            string
+	RevisionCode        string
+	UOM                 string
+	Quantity            int
+	CountryOfOrigin     string
+	LotID               string
+	PurchaseOrderNumber string
+}
+
+type InventorySnapshot struct {
+	VendorID  string
+	Locations []InventoryLocation
+}
+
+type InventoryLocation struct {
+	DistributionCenterID string
+	DistributionCenter   string
+	AsOf                 time.Time
+	Items                []InventoryItem
+}
+
+type InventoryItem struct {
+	GTIN            string
+	Quantity        int
+	UOM             string
+	InboundQuantity int
+}
+
+type Acknowledgement struct {
+	InterchangeID    string
+	SenderID         string
+	ReceiverID       string
+	ProductionOrTest string
+	Code             string
+	Documents        []AcknowledgedDocument
+}
+
+type AcknowledgedDocument struct {
+	TransactionType     string
+	PurchaseOrderNumber string
+	Code                string
+	Errors              []ValidationError
+}
+
+// Purchase Order
+
+func (s *Standard850V4) PurchaseOrder(ctx context.Context) PurchaseOrder {
+
+	purchaseOrder := PurchaseOrder{
+		Purpose:               s.Transaction.TransactionSetPurpose,
+		TypeCode:              s.Transaction.PurchaseOrderTypeCode,
+		Number:                s.Transaction.PurchaseOrderNumber,
+		ReleaseNumber:         s.Transaction.ReleaseNumber,
+		Date:                  s.Transaction.PODate,
+		Time:                  s.Transaction.POTime,
+		CurrencyCode:          s.Transaction.CurrencyCode,
+		PurchaserAccountID:    s.Transaction.PurchaserAccountID,
+		VendorID:              s.Transaction.VendorID,
+		StoreID:               s.Transaction.StoreID,
+		DistributionCenterID:  s.Transaction.DistributionCenterID,
+		RequestedShipDate:     s.Transaction.RequestedShipDate,
+		CancelDate:            s.Transaction.CancelDate,
+		CarrierRoutingDetails: s.Transaction.CarrierRoutingDetails,
+		DropShip:              s.Transaction.DropShipCode == "Y",
+		ShipTo: Address{
+			CompanyName:  s.Transaction.DeliverToCompanyName,
+			ContactName:  s.Transaction.DeliverToContactName,
+			Address1:     s.Transaction.DeliverToAddress1,
+			Address2:     s.Transaction.DeliverToAddress2,
+			CityName:     s.Transaction.DeliverToCityName,
+			StateCode:    s.Transaction.DeliverToStateCode,
+			PostalCode:   s.Transaction.DeliverToPostalCode,
+			CountryCode:  s.Transaction.DeliverToCountryCode,
+			PhoneNumber:  s.Transaction.DeliverToReceiversPhoneNumber,
+			EmailAddress: s.Transaction.ReceiversEmailAddress,
+		},
+		DeliveryInstructions: s.Transaction.SpecialDeliveryInstructions,
+		OrderInstructions:    s.Transaction.SpecialOrderInstructions,
+	}
+
+	// Lines
+	for _, lineItem := range s.LineItems {
+		purchaseOrder.Lines = append(purchaseOrder.Lines, OrderLine{
+			LineNumber:  lineItem.LineItemNumber,
+			GTIN:        lineItem.ItemIdentificationGTIN,
+			MasterStyle: lineItem.MasterStyle,
+			ColorCode:   lineItem.ColorCode,
+			SizeCode:    lineItem.SizeCode,
+			Quantity:    lineItem.QuantityOrdered,
+			UOM:         lineItem.UnitOrBasisForMeasurementCode,
+			UnitPrice:   lineItem.PurchaseUnitPrice,
+		})
+	}
+
+	// Charges
+	for _, otherCharge := range s.OtherCharges {
+		purchaseOrder.Charges = append(purchaseOrder.Charges, Charge{
+			LineNumber:  otherCharge.LineItemNumberForOtherCharges,
+			Description: otherCharge.OtherChargeDescription,
+			Amount:      otherCharge.OtherChargeAmount,
+		})
+	}
+
+	return purchaseOrder
+}
+
+func (s *Standard850V1) PurchaseOrder(ctx context.Context) PurchaseOrder {
+
+	standard850V4, _ := s.ToV4(ctx)
+
+	return standard850V4.PurchaseOrder(ctx)
+}
+
+func (s *PurchaseOrder) ToStandard850V4(ctx context.Context) Standard850V4 {
+
+	var dropShipCode string
+	if s.DropShip {
+		dropShipCode = "Y"
+	} else {
+		dropShipCode = "N"
+	}
+
+	standard850V4 := Standard850V4{
+		Transaction: Standard850V4Transaction{
+			TransactionSetPurpose:         s.Purpose,
+			PurchaseOrderTypeCode:         s.TypeCode,
+			PurchaseOrderNumber:           s.Number,
+			ReleaseNumber:                 s.ReleaseNumber,
+			PODate:                        s.Date,
+			POTime:                        s.Time,
+			CurrencyCode:                  s.CurrencyCode,
+			PurchaserAccountID:            s.PurchaserAccountID,
+			VendorID:                      s.VendorID,
+			StoreID:                       s.StoreID,
+			DistributionCenterID:          s.DistributionCenterID,
+			RequestedShipDate:             s.RequestedShipDate,
+			CancelDate:                    s.CancelDate,
+			CarrierRoutingDetails:         s.CarrierRoutingDetails,
+			DropShipCode:                  dropShipCode,
+			DeliverToCompanyName:          s.ShipTo.CompanyName,
+			DeliverToContactName:          s.ShipTo.ContactName,
+			DeliverToAddress1:             s.ShipTo.Address1,
+			DeliverToAddress2:             s.ShipTo.Address2,
+			DeliverToCityName:             s.ShipTo.CityName,
+			DeliverToStateCode:            s.ShipTo.StateCode,
+			DeliverToPostalCode:           s.ShipTo.PostalCode,
+			DeliverToCountryCode:          s.ShipTo.CountryCode,
+			DeliverToReceiversPhoneNumber: s.ShipTo.PhoneNumber,
+			ReceiversEmailAddress:         s.ShipTo.EmailAddress,
+			SpecialDeliveryInstructions:   s.DeliveryInstructions,
+			SpecialOrderInstructions:      s.OrderInstructions,
+		},
+	}
+
+	// Line Items
+	for _, line := range s.Lines {
+		standard850V4.LineItems = append(standard850V4.LineItems, Standard850V4LineItem{
+			LineItemNumber:                line.LineNumber,
+			ItemIdentificationGTIN:        line.GTIN,
+			MasterStyle:                   line.MasterStyle,
+			ColorCode:                     line.ColorCode,
+			SizeCode:                      line.SizeCode,
+			QuantityOrdered:               line.Quantity,
+			UnitOrBasisForMeasurementCode: line.UOM,
+			PurchaseUnitPrice:             line.UnitPrice,
+		})
+	}
+
+	// Other Charges
+	for _, charge := range s.Charges {
+		standard850V4.OtherCharges = append(standard850V4.OtherCharges, Standard850V4OtherCharge{
+			LineItemNumberForOtherCharges: charge.LineNumber,
+			OtherChargeDescription:        charge.Description,
+			OtherChargeAmount:             charge.Amount,
+		})
+	}
+
+	return standard850V4
+}
+
+func (s *PurchaseOrder) ToStandard850V1(ctx context.Context) Standard850V1 {
+
+	standard850V4 := s.ToStandard850V4(ctx)
+	standard850V1, _ := standard850V4.ToV1(ctx)
+
+	return standard850V1
+}
+
+// Warehouse Order
+
+func (s *Standard940V2) WarehouseOrder(ctx context.Context) WarehouseOrder {
+
+	standard850V4 := Standard850V4{
+		Transaction: Standard850V4Transaction(s.Transaction),
+	}
+	for _, lineItem := range s.LineItems {
+		standard850V4.LineItems = append(standard850V4.LineItems, Standard850V4LineItem(lineItem))
+	}
+	for _, otherCharge := range s.OtherCharges {
+		standard850V4.OtherCharges = append(standard850V4.OtherCharges, Standard850V4OtherCharge(otherCharge))
+	}
+
+	return WarehouseOrder{
+		PurchaseOrder:           standard850V4.PurchaseOrder(ctx),
+		ShipToCounty:            s.Transaction.DeliverToCountyProvinceTownTerritory,
+		ShipToSiteType:          s.Transaction.DeliverToCommercialOrResidentialSite,
+		DeliveryServiceLevel:    s.Transaction.DeliveryServiceLevel,
+		TrackingID:              s.Transaction.TrackingID,
+		CustomerPONumber:        s.Transaction.CustomerPONumber,
+		PromotionalCode:         s.Transaction.PromotionalCode,
+		AccountNumber:           s.Transaction.AccountNumber,
+		AccountName:             s.Transaction.NameOfAccount,
+		PurchasersAccountID:     s.Transaction.PurchasersAccountID,
+		ThirdPartyAccountNumber: s.Transaction.ThirdPartyAccountNumber,
+		CODForMerchandise:       s.Transaction.CODForMerchandise,
+		CODTags:                 s.Transaction.CODTagsIndicator,
+		NumberOfCases:           s.Trailer.NumberOfCases,
+		TotalQuantity:           s.Trailer.TotalQuantityOrdered,
+		LinesAmount:             s.Trailer.TotalMonetaryValue,
+		ChargesAmount:           s.Trailer.TotalMonetaryValueOfOtherCharges,
+		TotalAmount:             s.Trailer.PurchaseOrderTotalAmount,
+	}
+}
+
+func (s *Standard940V1) WarehouseOrder(ctx context.Context) WarehouseOrder {
+
+	standard940V2, _ := s.ToV2(ctx)
+
+	return standard940V2.WarehouseOrder(ctx)
+}
+
+func (s *WarehouseOrder) ToStandard940V2(ctx context.Context) Standard940V2 {
+
+	standard850V4 := s.PurchaseOrder.ToStandard850V4(ctx)
+
+	standard940V2 := Standard940V2{
+		Transaction: Standard940V2Transaction(standard850V4.Transaction),
+		Trailer: Standard940V2Trailer{
+			TotalQuantityOrdered:             s.TotalQuantity,
+			TotalMonetaryValue:               s.LinesAmount,
+			TotalMonetaryValueOfOtherCharges: s.ChargesAmount,
+			NumberOfCases:                    s.NumberOfCases,
+			PurchaseOrderTotalAmount:         s.TotalAmount,
+		},
+	}
+	standard940V2.Transaction.DeliverToCountyProvinceTownTerritory = s.ShipToCounty
+	standard940V2.Transaction.DeliverToCommercialOrResidentialSite = s.ShipToSiteType
+	standard940V2.Transaction.DeliveryServiceLevel = s.DeliveryServiceLevel
+	standard940V2.Transaction.TrackingID = s.TrackingID
+	standard940V2.Transaction.CustomerPONumber = s.CustomerPONumber
+	standard940V2.Transaction.PromotionalCode = s.PromotionalCode
+	standard940V2.Transaction.AccountNumber = s.AccountNumber
+	standard940V2.Transaction.NameOfAccount = s.AccountName
+	standard940V2.Transaction.PurchasersAccountID = s.PurchasersAccountID
+	standard940V2.Transaction.ThirdPartyAccountNumber = s.ThirdPartyAccountNumber
+	standard940V2.Transaction.CODForMerchandise = s.CODForMerchandise
+	standard940V2.Transaction.CODTagsIndicator = s.CODTags
+	for _, lineItem := range standard850V4.LineItems {
+		standard940V2.LineItems = append(standard940V2.LineItems, Standard940V2LineItem(lineItem))
+	}
+	for _, otherCharge := range standard850V4.OtherCharges {
+		standard940V2.OtherCharges = append(standard940V2.OtherCharges, Standard940V2OtherCharge(otherCharge))
+	}
+
+	return standard940V2
+}
+
+func (s *WarehouseOrder) ToStandard940V1(ctx context.Context) Standard940V1 {
+
+	standard940V2 := s.ToStandard940V2(ctx)
+	standard940V1, _ := standard940V2.ToV1(ctx)
+
+	return standard940V1
+}
+
+// Ship Notice
+
+func (s *Standard856V7) ShipNotice(ctx context.Context) ShipNotice {
+
+	shipNotice := ShipNotice{
+		Purpose:               s.Transaction.TransactionSetPurpose,
+		Number:                s.Transaction.ShipmentNumber,
+		Date:                  s.Transaction.ASNDate,
+		Time:                  s.Transaction.ASNTime,
+		ShipmentDate:          s.Transaction.ShipmentDate,
+		VendorID:              s.Transaction.VendorID,
+		PurchaserAccountID:    s.Transaction.PurchaserAccountID,
+		StoreID:               s.Transaction.StoreID,
+		DistributionCenterID:  s.Transaction.DistributionCenterID,
+		DropShip:              s.Transaction.DropShipCode == "Y",
+		BOLNumber:             s.Transaction.BOLNumber,
+		CarrierRoutingDetails: s.Transaction.CarrierRoutingDetails,
+		TrailerID:             s.Transaction.TrailerID,
+		ShipTo: Address{
+			CompanyName: s.Transaction.DeliverToCompanyName,
+			ContactName: s.Transaction.DeliverToContactName,
+			Address1:    s.Transaction.DeliverToAddress1,
+			Address2:    s.Transaction.DeliverToAddress2,
+			CityName:    s.Transaction.DeliverToCityName,
+			StateCode:   s.Transaction.DeliverToStateCode,
+			PostalCode:  s.Transaction.DeliverToPostalCode,
+			CountryCode: s.Transaction.DeliverToCountryCode,
+		},
+	}
+
+	// Pallets
+	for _, pallet := range s.Pallets {
+		shipNoticePallet := ShipNoticePallet{
+			ID: pallet.PalletID,
+		}
+
+		// Cartons
+		for _, shipment := range pallet.Shipments {
+			shipNoticeCarton := ShipNoticeCarton{
+				CaseNumber:            shipment.ManufacturersSerialCaseNumber,
+				PurchaseOrderNumber:   shipment.BuyersPurchaseOrderNumber,
+				PurchaseOrderTypeCode: shipment.PurchaseOrderTypeCode,
+				PurchaseOrderDate:     shipment.PODate,
+				PurchaseOrderTime:     shipment.POTime,
+				OrderNumber:           shipment.ManufacturersOrderNumber,
+				CarrierTrackingNumber: shipment.CarrierTrackingNumber,
+				TrackingID:            shipment.TrackingID,
+				Weight:                shipment.CaseWeight,
+				FreightCharge:         shipment.FreightCharge,
+			}
+
+			// Lines
+			for _, lineItem := range shipment.LineItems {
+				shipNoticeCarton.Lines = append(shipNoticeCarton.Lines, ShipNoticeLine{
+					LineNumber:          lineItem.LineItemNumber,
+					GTIN:                lineItem.ItemIdentificationGTIN,
+					MasterStyle:         lineItem.MasterStyle,
+					DetailStyle:         lineItem.DetailStyle,
+					ColorCode:           lineItem.ColorCode,
+					SizeCode:            lineItem.SizeCode,
+					RevisionCode:        lineItem.RevisionCode,
+					UOM:                 lineItem.UnitOrBasisForMeasurementCode,
+					Quantity:            lineItem.QuantityShipped,
+					CountryOfOrigin:     lineItem.CountryOfOrigin,
+					LotID:               lineItem.ManufacturersLotID,
+					PurchaseOrderNumber: lineItem.BuyersPurchaseOrderNumber,
+				})
+			}
+			shipNoticePallet.Cartons = append(shipNoticePallet.Cartons, shipNoticeCarton)
+		}
+		shipNotice.Pallets = append(shipNotice.Pallets, shipNoticePallet)
+	}
+
+	return shipNotice
+}
+
+func (s *Standard856V4) ShipNotice(ctx context.Context) ShipNotice {
+
+	standard856V7, _ := s.ToV7(ctx)
+
+	return standard856V7.ShipNotice(ctx)
+}
+
+// ShipNotices returns one ShipNotice per transaction.
+func (s *Standard856V5) ShipNotices(ctx context.Context) []ShipNotice {
+
+	standard856V7s, _ := s.ToV7(ctx)
+
+	var shipNotices []ShipNotice
+	for _, standard856V7 := range standard856V7s {
+		shipNotices = append(shipNotices, standard856V7.ShipNotice(ctx))
+	}
+
+	return shipNotices
+}
+
+func (s *ShipNotice) ToStandard856V7(ctx context.Context) Standard856V7 {
+
+	var dropShipCode string
+	if s.DropShip {
+		dropShipCode = "Y"
+	} else {
+		dropShipCode = "N"
+	}
+
+	standard856V7 := Standard856V7{
+		Transaction: Standard856V7Transaction{
+			TransactionSetPurpose: s.Purpose,
+			ShipmentNumber:        s.Number,
+			ASNDate:               s.Date,
+			ASNTime:               s.Time,
+			ShipmentDate:          s.ShipmentDate,
+			VendorID:              s.VendorID,
+			PurchaserAccountID:    s.PurchaserAccountID,
+			StoreID:               s.StoreID,
+			DistributionCenterID:  s.DistributionCenterID,
+			DropShipCode:          dropShipCode,
+			BOLNumber:             s.BOLNumber,
+			CarrierRoutingDetails: s.CarrierRoutingDetails,
+			TrailerID:             s.TrailerID,
+			DeliverToCompanyName:  s.ShipTo.CompanyName,
+			DeliverToContactName:  s.ShipTo.ContactName,
+			DeliverToAddress1:     s.ShipTo.Address1,
+			DeliverToAddress2:     s.ShipTo.Address2,
+			DeliverToCityName:     s.ShipTo.CityName,
+			DeliverToStateCode:    s.ShipTo.StateCode,
+			DeliverToPostalCode:   s.ShipTo.PostalCode,
+			DeliverToCountryCode:  s.ShipTo.CountryCode,
+		},
+	}
+
+	// Pallets
+	for _, shipNoticePallet := range s.Pallets {
+		pallet := Standard856V7Pallet{
+			PalletID: shipNoticePallet.ID,
+		}
+
+		// Shipments
+		for _, shipNoticeCarton := range shipNoticePallet.Cartons {
+			shipment := Standard856V7Shipment{
+				ManufacturersSerialCaseNumber: shipNoticeCarton.CaseNumber,
+				BuyersPurchaseOrderNumber:     shipNoticeCarton.PurchaseOrderNumber,
+				PurchaseOrderTypeCode:         shipNoticeCarton.PurchaseOrderTypeCode,
+				PODate:                        shipNoticeCarton.PurchaseOrderDate,
+				POTime:                        shipNoticeCarton.PurchaseOrderTime,
+				ManufacturersOrderNumber:      shipNoticeCarton.OrderNumber,
+				CarrierTrackingNumber:         shipNoticeCarton.CarrierTrackingNumber,
+				TrackingID:                    shipNoticeCarton.TrackingID,
+				CaseWeight:                    shipNoticeCarton.Weight,
+				FreightCharge:                 shipNoticeCarton.FreightCharge,
+			}
+
+			// Line Items
+			for _, line := range shipNoticeCarton.Lines {
+				shipment.LineItems = append(shipment.LineItems, Standard856V7LineItem{
+					LineItemNumber:                line.LineNumber,
+					ItemIdentificationGTIN:        line.GTIN,
+					MasterStyle:                   line.MasterStyle,
+					DetailStyle:                   line.DetailStyle,
+					ColorCode:                     line.ColorCode,
+					SizeCode:                      line.SizeCode,
+					RevisionCode:                  line.RevisionCode,
+					UnitOrBasisForMeasurementCode: line.UOM,
+					QuantityShipped:               line.Quantity,
+					CountryOfOrigin:               line.CountryOfOrigin,
+					ManufacturersLotID:            line.LotID,
+					BuyersPurchaseOrderNumber:     line.PurchaseOrderNumber,
+				})
+			}
+			pallet.Shipments = append(pallet.Shipments, shipment)
+		}
+		standard856V7.Pallets = append(standard856V7.Pallets, pallet)
+	}
+
+	return standard856V7
+}
+
+func (s *ShipNotice) ToStandard856V4(ctx context.Context) Standard856V4 {
+
+	standard856V7 := s.ToStandard856V7(ctx)
+	standard856V4, _ := standard856V7.ToV4(ctx)
+
+	return standard856V4
+}
+
+func (s *ShipNotice) ToStandard856V5(ctx context.Context) Standard856V5 {
+
+	standard856V7 := s.ToStandard856V7(ctx)
+	standard856V5, _ := standard856V7.ToV5(ctx)
+
+	return standard856V5
+}
+
+// Inventory Snapshot
+
+// InventorySnapshot returns one location per section. The as of time is left
+// zero when a section has no as of date.
+func (s *Standard846V3) InventorySnapshot(ctx context.Context) (InventorySnapshot, error) {
+
+	sections := s.Sections
+	if len(sections) == 0 {
+		sections = []Standard846V3Section{
+			Standard846V3Section{
+				Header:    s.Header,
+				LineItems: s.LineItems,
+				Trailer:   s.Trailer,
+			},
+		}
+	}
+
+	var inventorySnapshot InventorySnapshot
+	for _, section := range sections {
+		if inventorySnapshot.VendorID == "" {
+			inventorySnapshot.VendorID = section.Header.VendorID
+		}
+
+		inventoryLocation := InventoryLocation{
+			DistributionCenterID: section.Header.DistributionCenterID,
+			DistributionCenter:   section.Header.DistributionCenter,
+		}
+		if section.Header.AsOfDate != "" {
+			asOf, err := section.Header.AsOf(ctx)
+			if err != nil {
+				return InventorySnapshot{}, err
+			}
+			inventoryLocation.AsOf = asOf
+		}
+
+		// Items
+		for _, lineItem := range section.LineItems {
+			var inboundQuantity int
+			if lineItem.QuantityToArriveWithinTheNextTwoWeeks != "" {
+				var err error
+				inboundQuantity, err = strconv.Atoi(lineItem.QuantityToArriveWithinTheNextTwoWeeks)
+				if err != nil {
+					return InventorySnapshot{}, fmt.Errorf("846 inbound quantity for GTIN %s: %w", lineItem.ItemIdentificationGTIN, err)
+				}
+			}
+			inventoryLocation.Items = append(inventoryLocation.Items, InventoryItem{
+				GTIN:            lineItem.ItemIdentificationGTIN,
+				Quantity:        lineItem.CurrentInventoryLevel,
+				UOM:             lineItem.UnitOfMeasure,
+				InboundQuantity: inboundQuantity,
+			})
+		}
+		inventorySnapshot.Locations = append(inventorySnapshot.Locations, inventoryLocation)
+	}
+
+	return inventorySnapshot, nil
+}
+
+func (s *InventorySnapshot) ToStandard846V3(ctx context.Context) Standard846V3 {
+
+	var standard846V3 Standard846V3
+	for _, inventoryLocation := range s.Locations {
+		section := Standard846V3Section{
+			Header: Standard846V3TransactionHeader{
+				VendorID:             s.VendorID,
+				DistributionCenterID: inventoryLocation.DistributionCenterID,
+				DistributionCenter:   inventoryLocation.DistributionCenter,
+			},
+		}
+		if !inventoryLocation.AsOf.IsZero() {
+			section.Header.AsOfDate = inventoryLocation.AsOf.Format("20060102")
+			section.Header.AsOfTime = inventoryLocation.AsOf.Format("150405")
+			section.Header.TimeZone = inventoryLocation.AsOf.Location().String()
+		}
+
+		// Line Items
+		for _, item := range inventoryLocation.Items {
+			section.LineItems = append(section.LineItems, Standard846V3LineItem{
+				ItemIdentificationGTIN:                item.GTIN,
+				CurrentInventoryLevel:                 item.Quantity,
+				UnitOfMeasure:                         item.UOM,
+				QuantityToArriveWithinTheNextTwoWeeks: strconv.Itoa(item.InboundQuantity),
+			})
+		}
+		standard846V3.Sections = append(standard846V3.Sections, section)
+	}
+
+	return standard846V3
+}
+
+// Acknowledgement
+
+func (s *Standard997V3) Acknowledgement(ctx context.Context) Acknowledgement {
+
+	acknowledgement := Acknowledgement{
+		InterchangeID:    s.Body.InterchangeID,
+		SenderID:         s.Body.SenderID,
+		ReceiverID:       s.Body.ReceiverID,
+		ProductionOrTest: s.Body.ProductionOrTest,
+		Code:             s.Body.TransactionSetAcknowledgementCodes,
+	}
+
+	// Documents
+	for _, document := range s.Documents {
+		acknowledgedDocument := AcknowledgedDocument{
+			TransactionType:     document.TransactionType,
+			PurchaseOrderNumber: document.PurchaseOrderNumber,
+			Code:                document.TransactionSetAcknowledgementCode,
+		}
+
+		// Errors
+		for _, documentError := range document.Errors {
+			acknowledgedDocument.Errors = append(acknowledgedDocument.Errors, ValidationError{
+				PurchaseOrderNumber: document.PurchaseOrderNumber,
+				RecordType:          documentError.RecordType,
+				LineItemNumber:      documentError.RecordLineItemNumber,
+				Field:               documentError.FieldName,
+				Code:                documentError.ErrorCode,
+				Message:             documentError.ErrorMessage,
+				Line:                documentError.Line,
+				Column:              documentError.Column,
+			})
+		}
+		acknowledgement.Documents = append(acknowledgement.Documents, acknowledgedDocument)
+	}
+
+	return acknowledgement
+}
+
+func (s *Standard997V2) Acknowledgement(ctx context.Context) Acknowledgement {
+
+	standard997V3, _ := s.ToV3(ctx)
+
+	return standard997V3.Acknowledgement(ctx)
+}
+
+func (s *Standard997V1) Acknowledgement(ctx context.Context) Acknowledgement {
+
+	standard997V2, _ := s.ToV2(ctx)
+
+	return standard997V2.Acknowledgement(ctx)
+}
+
+func (s *Acknowledgement) ToStandard997V3(ctx context.Context) Standard997V3 {
+
+	standard997V3 := Standard997V3{
+		Body: Standard997V3Body{
+			InterchangeID:                      s.InterchangeID,
+			SenderID:                           s.SenderID,
+			ReceiverID:                         s.ReceiverID,
+			ProductionOrTest:                   s.ProductionOrTest,
+			TransactionSetAcknowledgementCodes: s.Code,
+		},
+	}
+
+	// Documents
+	for _, acknowledgedDocument := range s.Documents {
+		document := Standard997V3Document{
+			TransactionType:                   acknowledgedDocument.TransactionType,
+			PurchaseOrderNumber:               acknowledgedDocument.PurchaseOrderNumber,
+			TransactionSetAcknowledgementCode: acknowledgedDocument.Code,
+		}
+
+		// Errors
+		for _, validationError := range acknowledgedDocument.Errors {
+			document.Errors = append(document.Errors, Standard997V3Error{
+				RecordType:           validationError.RecordType,
+				RecordLineItemNumber: validationError.LineItemNumber,
+				FieldName:            validationError.Field,
+				ErrorCode:            validationError.Code,
+				ErrorMessage:         validationError.Message,
+				Line:                 validationError.Line,
+				Column:               validationError.Column,
+			})
+		}
+		standard997V3.Documents = append(standard997V3.Documents, document)
+	}
+
+	return standard997V3
+}
+
+func (s *Acknowledgement) ToStandard997V2(ctx context.Context) Standard997V2 {
+
+	standard997V3 := s.ToStandard997V3(ctx)
+	standard997V2, _ := standard997V3.ToV2(ctx)
+
+	return standard997V2
+}
+
+func (s *Acknowledgement) ToStandard997V1(ctx context.Context) Standard997V1 {
+
+	standard997V2 := s.ToStandard997V2(ctx)
+	standard997V1, _ := standard997V2.ToV1(ctx)
+
+	return standard997V1
+}
@@ -0,0 +1,177 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurchaseOrder(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard850V4 := range Standard850V4s {
+		purchaseOrder := standard850V4.PurchaseOrder(ctx)
+		assert.Equal(t, standard850V4.Transaction.PurchaseOrderNumber, purchaseOrder.Number)
+		assert.Len(t, purchaseOrder.Lines, len(standard850V4.LineItems))
+
+		rendered := purchaseOrder.ToStandard850V4(ctx)
+		assert.Equal(t, purchaseOrder, rendered.PurchaseOrder(ctx))
+
+		standard850V1 := purchaseOrder.ToStandard850V1(ctx)
+		assert.Equal(t, purchaseOrder.Number, standard850V1.Transaction.PurchaseOrderNumber)
+		fromV1 := standard850V1.PurchaseOrder(ctx)
+		assert.Equal(t, purchaseOrder.Lines, fromV1.Lines)
+		assert.Empty(t, fromV1.DistributionCenterID)
+	}
+
+}
+
+func TestWarehouseOrder(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard940V2 := range Standard940V2s {
+		warehouseOrder := standard940V2.WarehouseOrder(ctx)
+		assert.Equal(t, standard940V2.Transaction.DistributionCenterID, warehouseOrder.DistributionCenterID)
+
+		rendered := warehouseOrder.ToStandard940V2(ctx)
+		assert.Equal(t, warehouseOrder, rendered.WarehouseOrder(ctx))
+
+		standard940V1 := warehouseOrder.ToStandard940V1(ctx)
+		fromV1 := standard940V1.WarehouseOrder(ctx)
+		assert.Equal(t, warehouseOrder.Lines, fromV1.Lines)
+		assert.Empty(t, fromV1.TrackingID)
+	}
+
+	// Round Trip
+	standard940V2 := Standard940V2s[0]
+	standard940V2.LineItems = append([]Standard940V2LineItem{}, Standard940V2s[0].LineItems...)
+	standard940V2.OtherCharges = append([]Standard940V2OtherCharge{}, Standard940V2s[0].OtherCharges...)
+	standard940V2.Transaction.ThirdPartyAccountNumber = "998877"
+	err := standard940V2.Prep(ctx)
+	assert.Nil(t, err)
+
+	warehouseOrder := standard940V2.WarehouseOrder(ctx)
+	assert.Equal(t, "Y", warehouseOrder.CODForMerchandise)
+	assert.Equal(t, 3530, warehouseOrder.TotalAmount)
+
+	rendered := warehouseOrder.ToStandard940V2(ctx)
+	assert.Equal(t, standard940V2.Transaction.DeliverToCountyProvinceTownTerritory, rendered.Transaction.DeliverToCountyProvinceTownTerritory)
+	assert.Equal(t, standard940V2.Transaction.DeliveryServiceLevel, rendered.Transaction.DeliveryServiceLevel)
+	assert.Equal(t, standard940V2.Transaction.CODForMerchandise, rendered.Transaction.CODForMerchandise)
+	assert.Equal(t, standard940V2.Transaction.TrackingID, rendered.Transaction.TrackingID)
+	assert.Equal(t, standard940V2.Transaction.DeliverToCommercialOrResidentialSite, rendered.Transaction.DeliverToCommercialOrResidentialSite)
+	assert.Equal(t, standard940V2.Transaction.CODTagsIndicator, rendered.Transaction.CODTagsIndicator)
+	assert.Equal(t, standard940V2.Transaction.ThirdPartyAccountNumber, rendered.Transaction.ThirdPartyAccountNumber)
+	assert.Equal(t, standard940V2.Trailer.NumberOfCases, rendered.Trailer.NumberOfCases)
+	assert.Equal(t, standard940V2.Trailer.TotalMonetaryValue, rendered.Trailer.TotalMonetaryValue)
+	assert.Equal(t, standard940V2.Trailer.TotalMonetaryValueOfOtherCharges, rendered.Trailer.TotalMonetaryValueOfOtherCharges)
+	assert.Equal(t, standard940V2.Trailer.PurchaseOrderTotalAmount, rendered.Trailer.PurchaseOrderTotalAmount)
+
+}
+
+func TestShipNotice(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard856V7 := range Standard856V7s {
+		shipNotice := standard856V7.ShipNotice(ctx)
+		if assert.Len(t, shipNotice.Pallets, 1) && assert.Len(t, shipNotice.Pallets[0].Cartons, 1) {
+			assert.Equal(t, "345345", shipNotice.Pallets[0].Cartons[0].CaseNumber)
+			assert.Len(t, shipNotice.Pallets[0].Cartons[0].Lines, 2)
+		}
+
+		rendered := shipNotice.ToStandard856V7(ctx)
+		assert.Equal(t, shipNotice, rendered.ShipNotice(ctx))
+
+		standard856V4 := shipNotice.ToStandard856V4(ctx)
+		fromV4 := standard856V4.ShipNotice(ctx)
+		assert.Equal(t, shipNotice.Pallets[0].Cartons[0].Lines, fromV4.Pallets[0].Cartons[0].Lines)
+
+		standard856V5 := shipNotice.ToStandard856V5(ctx)
+		fromV5 := standard856V5.ShipNotices(ctx)
+		if assert.Len(t, fromV5, 1) {
+			assert.Equal(t, shipNotice.Number, fromV5[0].Number)
+			assert.Equal(t, shipNotice.Pallets[0].Cartons[0].CarrierTrackingNumber, fromV5[0].Pallets[0].Cartons[0].CarrierTrackingNumber)
+			if assert.Len(t, fromV5[0].Pallets[0].Cartons[0].Lines, 2) {
+				assert.Equal(t, "34534534", fromV5[0].Pallets[0].Cartons[0].Lines[1].PurchaseOrderNumber)
+				assert.Equal(t, 6, fromV5[0].Pallets[0].Cartons[0].Lines[1].Quantity)
+			}
+		}
+	}
+
+}
+
+func TestInventorySnapshot(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/846.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard846V3 Standard846V3
+	err := standard846V3.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	inventorySnapshot, err := standard846V3.InventorySnapshot(ctx)
+	assert.Nil(t, err)
+	if assert.Len(t, inventorySnapshot.Locations, 2) {
+		assert.Equal(t, "RNO1", inventorySnapshot.Locations[1].DistributionCenterID)
+		assert.False(t, inventorySnapshot.Locations[1].AsOf.IsZero())
+		assert.Len(t, inventorySnapshot.Locations[0].Items, 3)
+	}
+
+	rendered := inventorySnapshot.ToStandard846V3(ctx)
+	renderedSnapshot, err := rendered.InventorySnapshot(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, inventorySnapshot, renderedSnapshot)
+
+}
+
+func TestAcknowledgement(t *testing.T) {
+
+	ctx := context.Background()
+
+	acknowledgement := Acknowledgement{
+		InterchangeID: "123456789",
+		SenderID:      "014628093",
+		Code:          Standard997V3PartiallyAccepted,
+		Documents: []AcknowledgedDocument{
+			AcknowledgedDocument{
+				TransactionType:     "850",
+				PurchaseOrderNumber: "12345678",
+				Code:                Standard997V3Accepted,
+			},
+			AcknowledgedDocument{
+				TransactionType:     "850",
+				PurchaseOrderNumber: "12345679",
+				Code:                Standard997V3Rejected,
+				Errors: []ValidationError{
+					ValidationError{
+						PurchaseOrderNumber: "12345679",
+						RecordType:          "02",
+						LineItemNumber:      1,
+						Field:               "ItemIdentificationGTIN",
+						Code:                ValidationCodeUnknownGTIN,
+						Message:             "unknown GTIN",
+					},
+				},
+			},
+		},
+	}
+
+	standard997V3 := acknowledgement.ToStandard997V3(ctx)
+	assert.Equal(t, acknowledgement, standard997V3.Acknowledgement(ctx))
+
+	standard997V1 := acknowledgement.ToStandard997V1(ctx)
+	assert.Equal(t, "123456789", standard997V1.Body.InterchangeID)
+	fromV1 := standard997V1.Acknowledgement(ctx)
+	assert.Equal(t, Standard997V3PartiallyAccepted, fromV1.Code)
+	assert.Empty(t, fromV1.Documents)
+
+}
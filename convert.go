@@ -0,0 +1,542 @@
+package easi
+
+import (
+	"context"
+	"reflect"
+)
+
+// ConversionReport lists what a conversion between versions could not carry
+// over. Dropped names the source fields that held a value the target has no
+// field for, Defaulted names the target fields the source has no field for.
+// Fields are named by their path, as in "Transaction.DropShipCode".
+type ConversionReport struct {
+	Dropped   []string
+	Defaulted []string
+}
+
+type conversionFields struct {
+	Renames map[string]string
+	Filled  []string
+	Moved   []string
+}
+
+func (s *ConversionReport) drop(field string) {
+
+	for _, dropped := range s.Dropped {
+		if dropped == field {
+			return
+		}
+	}
+	s.Dropped = append(s.Dropped, field)
+}
+
+func (s *ConversionReport) defaulted(field string) {
+
+	for _, defaulted := range s.Defaulted {
+		if defaulted == field {
+			return
+		}
+	}
+	s.Defaulted = append(s.Defaulted, field)
+}
+
+// convertFields copies the fields of src into the same named fields of dst,
+// which must be a pointer to a struct. Renames maps target to source field
+// names, Filled target fields and Moved source fields are handled by the
+// caller. Slices and nested structs are left to the caller as well.
+func convertFields(report *ConversionReport, path string, dst interface{}, src interface{}, fields conversionFields) {
+
+	dstValue := reflect.ValueOf(dst).Elem()
+	srcValue := reflect.ValueOf(src)
+
+	used := map[string]bool{}
+	for _, moved := range fields.Moved {
+		used[moved] = true
+	}
+	filled := map[string]bool{}
+	for _, name := range fields.Filled {
+		filled[name] = true
+	}
+
+	// Target
+	for i := 0; i < dstValue.NumField(); i++ {
+		field := dstValue.Type().Field(i)
+		if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Struct || filled[field.Name] {
+			continue
+		}
+		name := field.Name
+		if renamed, ok := fields.Renames[name]; ok {
+			name = renamed
+		}
+		srcField, ok := srcValue.Type().FieldByName(name)
+		if !ok || srcField.Type != field.Type {
+			report.defaulted(path + field.Name)
+			continue
+		}
+		used[name] = true
+		dstValue.Field(i).Set(srcValue.FieldByIndex(srcField.Index))
+	}
+
+	// Source
+	for i := 0; i < srcValue.NumField(); i++ {
+		field := srcValue.Type().Field(i)
+		if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Struct || used[field.Name] {
+			continue
+		}
+		if !srcValue.Field(i).IsZero() {
+			report.drop(path + field.Name)
+		}
+	}
+}
+
+func (s *EnvelopeHeaderV2) ToV3(ctx context.Context) EnvelopeHeaderV3 {
+
+	envelopeHeaderV3 := EnvelopeHeaderV3(*s)
+	envelopeHeaderV3.VersionNumber = "3.0"
+
+	return envelopeHeaderV3
+}
+
+func (s *EnvelopeHeaderV3) ToV2(ctx context.Context) EnvelopeHeaderV2 {
+
+	envelopeHeaderV2 := EnvelopeHeaderV2(*s)
+	envelopeHeaderV2.VersionNumber = "2.0"
+
+	return envelopeHeaderV2
+}
+
+func (s *EnvelopeTrailerV2) ToV3(ctx context.Context) EnvelopeTrailerV3 {
+
+	return EnvelopeTrailerV3(*s)
+}
+
+func (s *EnvelopeTrailerV3) ToV2(ctx context.Context) EnvelopeTrailerV2 {
+
+	return EnvelopeTrailerV2(*s)
+}
+
+func (s *Standard850V1) ToV4(ctx context.Context) (Standard850V4, ConversionReport) {
+
+	var report ConversionReport
+	standard850V4 := Standard850V4{
+		EnvelopeHeaderV3:  s.EnvelopeHeaderV2.ToV3(ctx),
+		EnvelopeTrailerV3: s.EnvelopeTrailerV2.ToV3(ctx),
+	}
+
+	// Transaction
+	convertFields(&report, "Transaction.", &standard850V4.Transaction, s.Transaction, conversionFields{})
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		var x Standard850V4LineItem
+		convertFields(&report, "LineItems.", &x, lineItem, conversionFields{})
+		standard850V4.LineItems = append(standard850V4.LineItems, x)
+	}
+
+	// Other Charges
+	for _, otherCharge := range s.OtherCharges {
+		var x Standard850V4OtherCharge
+		convertFields(&report, "OtherCharges.", &x, otherCharge, conversionFields{})
+		standard850V4.OtherCharges = append(standard850V4.OtherCharges, x)
+	}
+
+	// Trailer
+	convertFields(&report, "Trailer.", &standard850V4.Trailer, s.Trailer, conversionFields{})
+
+	return standard850V4, report
+}
+
+func (s *Standard850V4) ToV1(ctx context.Context) (Standard850V1, ConversionReport) {
+
+	var report ConversionReport
+	standard850V1 := Standard850V1{
+		EnvelopeHeaderV2:  s.EnvelopeHeaderV3.ToV2(ctx),
+		EnvelopeTrailerV2: s.EnvelopeTrailerV3.ToV2(ctx),
+	}
+
+	// Transaction
+	convertFields(&report, "Transaction.", &standard850V1.Transaction, s.Transaction, conversionFields{})
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		var x Standard850V1LineItem
+		convertFields(&report, "LineItems.", &x, lineItem, conversionFields{})
+		standard850V1.LineItems = append(standard850V1.LineItems, x)
+	}
+
+	// Other Charges
+	for _, otherCharge := range s.OtherCharges {
+		var x Standard850V1OtherCharge
+		convertFields(&report, "OtherCharges.", &x, otherCharge, conversionFields{})
+		standard850V1.OtherCharges = append(standard850V1.OtherCharges, x)
+	}
+
+	// Trailer
+	convertFields(&report, "Trailer.", &standard850V1.Trailer, s.Trailer, conversionFields{})
+
+	return standard850V1, report
+}
+
+func (s *Standard997V1) ToV2(ctx context.Context) (Standard997V2, ConversionReport) {
+
+	var report ConversionReport
+	standard997V2 := Standard997V2{
+		EnvelopeHeaderV3:  s.EnvelopeHeaderV2.ToV3(ctx),
+		EnvelopeTrailerV3: s.EnvelopeTrailerV2.ToV3(ctx),
+	}
+
+	// Body
+	convertFields(&report, "Body.", &standard997V2.Body, s.Body, conversionFields{})
+
+	return standard997V2, report
+}
+
+func (s *Standard997V2) ToV1(ctx context.Context) (Standard997V1, ConversionReport) {
+
+	var report ConversionReport
+	standard997V1 := Standard997V1{
+		EnvelopeHeaderV2:  s.EnvelopeHeaderV3.ToV2(ctx),
+		EnvelopeTrailerV2: s.EnvelopeTrailerV3.ToV2(ctx),
+	}
+
+	// Body
+	convertFields(&report, "Body.", &standard997V1.Body, s.Body, conversionFields{})
+
+	return standard997V1, report
+}
+
+// ToV7 keeps the pallet and shipment hierarchy. The case and order numbers a
+// V4 line item repeats from its shipment are not reported as dropped.
+func (s *Standard856V4) ToV7(ctx context.Context) (Standard856V7, ConversionReport) {
+
+	var report ConversionReport
+	standard856V7 := Standard856V7{
+		EnvelopeHeaderV3:  s.EnvelopeHeaderV2.ToV3(ctx),
+		EnvelopeTrailerV3: s.EnvelopeTrailerV2.ToV3(ctx),
+	}
+
+	// Transaction
+	convertFields(&report, "Transaction.", &standard856V7.Transaction, s.Transaction, conversionFields{})
+
+	// Pallets
+	for _, pallet := range s.Pallets {
+		var x Standard856V7Pallet
+		convertFields(&report, "Pallets.", &x, pallet, conversionFields{})
+
+		// Shipments
+		for _, shipment := range pallet.Shipments {
+			var y Standard856V7Shipment
+			convertFields(&report, "Pallets.Shipments.", &y, shipment, conversionFields{})
+
+			// Line Items
+			for _, lineItem := range shipment.LineItems {
+				var moved []string
+				if lineItem.ManufacturersSerialCaseNumber == shipment.ManufacturersSerialCaseNumber {
+					moved = append(moved, "ManufacturersSerialCaseNumber")
+				}
+				if lineItem.ManufacturersOrderNumber == shipment.ManufacturersOrderNumber {
+					moved = append(moved, "ManufacturersOrderNumber")
+				}
+				var z Standard856V7LineItem
+				convertFields(&report, "Pallets.Shipments.LineItems.", &z, lineItem, conversionFields{
+					Renames: map[string]string{
+						"DetailSectionLoopB": "DetailSectionLoopA",
+						"QuantityShipped":    "Quantity",
+					},
+					Moved: moved,
+				})
+				y.LineItems = append(y.LineItems, z)
+			}
+			x.Shipments = append(x.Shipments, y)
+		}
+		standard856V7.Pallets = append(standard856V7.Pallets, x)
+	}
+
+	// Trailer
+	convertFields(&report, "Trailer.", &standard856V7.Trailer, s.Trailer, conversionFields{})
+
+	return standard856V7, report
+}
+
+func (s *Standard856V7) ToV4(ctx context.Context) (Standard856V4, ConversionReport) {
+
+	var report ConversionReport
+	standard856V4 := Standard856V4{
+		EnvelopeHeaderV2:  s.EnvelopeHeaderV3.ToV2(ctx),
+		EnvelopeTrailerV2: s.EnvelopeTrailerV3.ToV2(ctx),
+	}
+
+	// Transaction
+	convertFields(&report, "Transaction.", &standard856V4.Transaction, s.Transaction, conversionFields{})
+
+	// Pallets
+	for _, pallet := range s.Pallets {
+		var x Standard856V4Pallet
+		convertFields(&report, "Pallets.", &x, pallet, conversionFields{})
+
+		// Shipments
+		for _, shipment := range pallet.Shipments {
+			var y Standard856V4Shipment
+			convertFields(&report, "Pallets.Shipments.", &y, shipment, conversionFields{})
+
+			// Line Items
+			for _, lineItem := range shipment.LineItems {
+				z := Standard856V4LineItem{
+					ManufacturersSerialCaseNumber: shipment.ManufacturersSerialCaseNumber,
+					ManufacturersOrderNumber:      shipment.ManufacturersOrderNumber,
+				}
+				convertFields(&report, "Pallets.Shipments.LineItems.", &z, lineItem, conversionFields{
+					Renames: map[string]string{
+						"DetailSectionLoopA": "DetailSectionLoopB",
+						"Quantity":           "QuantityShipped",
+					},
+					Filled: []string{"ManufacturersSerialCaseNumber", "ManufacturersOrderNumber"},
+				})
+				y.LineItems = append(y.LineItems, z)
+			}
+			x.Shipments = append(x.Shipments, y)
+		}
+		standard856V4.Pallets = append(standard856V4.Pallets, x)
+	}
+
+	// Trailer
+	convertFields(&report, "Trailer.", &standard856V4.Trailer, s.Trailer, conversionFields{})
+
+	return standard856V4, report
+}
+
+// ToV7 returns one Standard856V7 per transaction. The line items of a pallet
+// are grouped into one shipment per case number, and each shipment takes the
+// carrier tracking number of its transaction.
+func (s *Standard856V5) ToV7(ctx context.Context) ([]Standard856V7, ConversionReport) {
+
+	var report ConversionReport
+	var standard856V7s []Standard856V7
+	for _, transaction := range s.Transactions {
+		standard856V7 := Standard856V7{
+			EnvelopeHeaderV3:  s.EnvelopeHeaderV2.ToV3(ctx),
+			EnvelopeTrailerV3: s.EnvelopeTrailerV2.ToV3(ctx),
+		}
+
+		// Transaction
+		convertFields(&report, "Transaction.", &standard856V7.Transaction, transaction.Header, conversionFields{
+			Moved: []string{"CarrierTrackingNumber"},
+		})
+
+		// Pallets
+		for _, pallet := range transaction.Pallets {
+			var x Standard856V7Pallet
+			convertFields(&report, "Pallets.", &x, pallet, conversionFields{})
+
+			// Shipments
+			shipmentKeys := map[string]int{}
+			for _, lineItem := range pallet.LineItems {
+				shipmentKey, ok := shipmentKeys[lineItem.ManufacturersSerialCaseNumber]
+				if !ok {
+					y := Standard856V7Shipment{
+						CarrierTrackingNumber:         transaction.Header.CarrierTrackingNumber,
+						ManufacturersSerialCaseNumber: lineItem.ManufacturersSerialCaseNumber,
+						BuyersPurchaseOrderNumber:     lineItem.BuyersPurchaseOrderNumber,
+						ManufacturersOrderNumber:      lineItem.ManufacturersOrderNumber,
+					}
+					convertFields(&report, "Pallets.Shipments.", &y, struct{}{}, conversionFields{
+						Filled: []string{"CarrierTrackingNumber", "ManufacturersSerialCaseNumber", "BuyersPurchaseOrderNumber", "ManufacturersOrderNumber"},
+					})
+					x.Shipments = append(x.Shipments, y)
+					shipmentKey = len(x.Shipments) - 1
+					shipmentKeys[lineItem.ManufacturersSerialCaseNumber] = shipmentKey
+				}
+
+				// Line Items
+				var z Standard856V7LineItem
+				convertFields(&report, "Pallets.Shipments.LineItems.", &z, lineItem, conversionFields{
+					Moved: []string{"ManufacturersSerialCaseNumber", "ManufacturersOrderNumber"},
+				})
+				x.Shipments[shipmentKey].LineItems = append(x.Shipments[shipmentKey].LineItems, z)
+			}
+			standard856V7.Pallets = append(standard856V7.Pallets, x)
+		}
+
+		// Trailer
+		convertFields(&report, "Trailer.", &standard856V7.Trailer, transaction.Trailer, conversionFields{})
+
+		standard856V7s = append(standard856V7s, standard856V7)
+	}
+
+	return standard856V7s, report
+}
+
+// ToV5 returns a single transaction. Line items carry the case, order and,
+// when they have none of their own, purchase order number of their shipment.
+// The transaction keeps the carrier tracking number only when every shipment
+// shares it.
+func (s *Standard856V7) ToV5(ctx context.Context) (Standard856V5, ConversionReport) {
+
+	var report ConversionReport
+	standard856V5 := Standard856V5{
+		EnvelopeHeaderV2:  s.EnvelopeHeaderV3.ToV2(ctx),
+		EnvelopeTrailerV2: s.EnvelopeTrailerV3.ToV2(ctx),
+	}
+
+	// Carrier Tracking Number
+	var carrierTrackingNumber string
+	var carrierTrackingNumbers int
+	for _, pallet := range s.Pallets {
+		for _, shipment := range pallet.Shipments {
+			if shipment.CarrierTrackingNumber != "" && shipment.CarrierTrackingNumber != carrierTrackingNumber {
+				carrierTrackingNumber = shipment.CarrierTrackingNumber
+				carrierTrackingNumbers++
+			}
+		}
+	}
+	if carrierTrackingNumbers > 1 {
+		carrierTrackingNumber = ""
+		report.drop("Pallets.Shipments.CarrierTrackingNumber")
+	}
+
+	// Transaction
+	transaction := Standard856V5Transaction{
+		Header: Standard856V5TransactionHeader{
+			CarrierTrackingNumber: carrierTrackingNumber,
+		},
+	}
+	convertFields(&report, "Transaction.", &transaction.Header, s.Transaction, conversionFields{
+		Filled: []string{"CarrierTrackingNumber"},
+	})
+
+	// Pallets
+	for _, pallet := range s.Pallets {
+		var x Standard856V5Pallet
+		convertFields(&report, "Pallets.", &x, pallet, conversionFields{})
+
+		// Shipments
+		for _, shipment := range pallet.Shipments {
+			convertFields(&report, "Pallets.Shipments.", &struct{}{}, shipment, conversionFields{
+				Moved: []string{"CarrierTrackingNumber", "ManufacturersSerialCaseNumber", "BuyersPurchaseOrderNumber", "ManufacturersOrderNumber"},
+			})
+
+			// Line Items
+			for _, lineItem := range shipment.LineItems {
+				z := Standard856V5LineItem{
+					ManufacturersSerialCaseNumber: shipment.ManufacturersSerialCaseNumber,
+					ManufacturersOrderNumber:      shipment.ManufacturersOrderNumber,
+				}
+				convertFields(&report, "Pallets.Shipments.LineItems.", &z, lineItem, conversionFields{
+					Filled: []string{"ManufacturersSerialCaseNumber", "ManufacturersOrderNumber"},
+				})
+				if z.BuyersPurchaseOrderNumber == "" {
+					z.BuyersPurchaseOrderNumber = shipment.BuyersPurchaseOrderNumber
+				}
+				x.LineItems = append(x.LineItems, z)
+			}
+		}
+		transaction.Pallets = append(transaction.Pallets, x)
+	}
+
+	// Trailer
+	convertFields(&report, "Trailer.", &transaction.Trailer, s.Trailer, conversionFields{})
+
+	standard856V5.Transactions = append(standard856V5.Transactions, transaction)
+
+	return standard856V5, report
+}
+
+func (s *Standard940V1) ToV2(ctx context.Context) (Standard940V2, ConversionReport) {
+
+	var report ConversionReport
+	standard940V2 := Standard940V2{
+		EnvelopeHeaderV3:  s.EnvelopeHeaderV3,
+		EnvelopeTrailerV3: s.EnvelopeTrailerV3,
+	}
+
+	// Transaction
+	convertFields(&report, "Transaction.", &standard940V2.Transaction, s.Transaction, conversionFields{})
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		var x Standard940V2LineItem
+		convertFields(&report, "LineItems.", &x, lineItem, conversionFields{})
+		standard940V2.LineItems = append(standard940V2.LineItems, x)
+	}
+
+	// Other Charges
+	for _, otherCharge := range s.OtherCharges {
+		var x Standard940V2OtherCharge
+		convertFields(&report, "OtherCharges.", &x, otherCharge, conversionFields{})
+		standard940V2.OtherCharges = append(standard940V2.OtherCharges, x)
+	}
+
+	// Trailer
+	convertFields(&report, "Trailer.", &standard940V2.Trailer, s.Trailer, conversionFields{})
+
+	return standard940V2, report
+}
+
+func (s *Standard940V2) ToV1(ctx context.Context) (Standard940V1, ConversionReport) {
+
+	var report ConversionReport
+	standard940V1 := Standard940V1{
+		EnvelopeHeaderV3:  s.EnvelopeHeaderV3,
+		EnvelopeTrailerV3: s.EnvelopeTrailerV3,
+	}
+
+	// Transaction
+	convertFields(&report, "Transaction.", &standard940V1.Transaction, s.Transaction, conversionFields{})
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		var x Standard940V1LineItem
+		convertFields(&report, "LineItems.", &x, lineItem, conversionFields{})
+		standard940V1.LineItems = append(standard940V1.LineItems, x)
+	}
+
+	// Other Charges
+	for _, otherCharge := range s.OtherCharges {
+		var x Standard940V1OtherCharge
+		convertFields(&report, "OtherCharges.", &x, otherCharge, conversionFields{})
+		standard940V1.OtherCharges = append(standard940V1.OtherCharges, x)
+	}
+
+	// Trailer
+	convertFields(&report, "Trailer.", &standard940V1.Trailer, s.Trailer, conversionFields{})
+
+	return standard940V1, report
+}
+
+func (s *Standard997V2) ToV3(ctx context.Context) (Standard997V3, ConversionReport) {
+
+	var report ConversionReport
+	standard997V3 := Standard997V3{
+		EnvelopeHeaderV3:  s.EnvelopeHeaderV3,
+		EnvelopeTrailerV3: s.EnvelopeTrailerV3,
+	}
+
+	// Body
+	convertFields(&report, "Body.", &standard997V3.Body, s.Body, conversionFields{})
+
+	return standard997V3, report
+}
+
+// ToV2 keeps the overall acknowledgement code. The per-document codes and
+// errors have no place in a V2 acknowledgement and are reported as dropped.
+func (s *Standard997V3) ToV2(ctx context.Context) (Standard997V2, ConversionReport) {
+
+	var report ConversionReport
+	standard997V2 := Standard997V2{
+		EnvelopeHeaderV3:  s.EnvelopeHeaderV3,
+		EnvelopeTrailerV3: s.EnvelopeTrailerV3,
+	}
+
+	// Body
+	convertFields(&report, "Body.", &standard997V2.Body, s.Body, conversionFields{})
+
+	// Documents
+	for _, document := range s.Documents {
+		convertFields(&report, "Documents.", &struct{}{}, document, conversionFields{})
+		for _, documentError := range document.Errors {
+			convertFields(&report, "Documents.Errors.", &struct{}{}, documentError, conversionFields{})
+		}
+	}
+
+	return standard997V2, report
+}
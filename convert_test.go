@@ -0,0 +1,179 @@
+package easi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandard850V1ToV4(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard850V1 := range standard850V1s {
+		standard850V4, report := standard850V1.ToV4(ctx)
+		assert.Equal(t, "3.0", standard850V4.EnvelopeHeaderV3.VersionNumber)
+		assert.Equal(t, standard850V1.Transaction.PurchaseOrderNumber, standard850V4.Transaction.PurchaseOrderNumber)
+		assert.Len(t, standard850V4.LineItems, len(standard850V1.LineItems))
+		assert.Empty(t, report.Dropped)
+		assert.Contains(t, report.Defaulted, "Transaction.DistributionCenterID")
+		assert.Contains(t, report.Defaulted, "Trailer.PurchaseOrderTotalAmount")
+
+		back, backReport := standard850V4.ToV1(ctx)
+		assert.Equal(t, "2.0", back.EnvelopeHeaderV2.VersionNumber)
+		assert.Equal(t, standard850V1.Transaction, back.Transaction)
+		assert.Equal(t, standard850V1.LineItems, back.LineItems)
+		assert.Empty(t, backReport.Dropped)
+	}
+
+}
+
+func TestStandard850V4ToV1(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard850V4 := range Standard850V4s {
+		standard850V1, report := standard850V4.ToV1(ctx)
+		assert.Equal(t, standard850V4.Transaction.PurchaseOrderNumber, standard850V1.Transaction.PurchaseOrderNumber)
+		assert.Equal(t, []string{"Transaction.DistributionCenterID"}, report.Dropped)
+	}
+
+}
+
+func TestStandard997V2ToV1(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard997V2 := range Standard997V2s {
+		standard997V1, report := standard997V2.ToV1(ctx)
+		assert.Equal(t, standard997V2.Body.InterchangeID, standard997V1.Body.InterchangeID)
+		assert.Equal(t, standard997V2.EnvelopeHeaderV3.SenderID, standard997V1.EnvelopeHeaderV2.SenderID)
+		assert.Empty(t, report.Dropped)
+		assert.Empty(t, report.Defaulted)
+
+		back, _ := standard997V1.ToV2(ctx)
+		assert.Equal(t, standard997V2.Body, back.Body)
+	}
+
+}
+
+func TestStandard856V7ToV5(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard856V7 := range Standard856V7s {
+		standard856V5, report := standard856V7.ToV5(ctx)
+		assert.Contains(t, report.Dropped, "Pallets.Shipments.TrackingID")
+		assert.NotContains(t, report.Dropped, "Pallets.Shipments.CarrierTrackingNumber")
+		if assert.Len(t, standard856V5.Transactions, 1) {
+			transaction := standard856V5.Transactions[0]
+			assert.Equal(t, "986979879878", transaction.Header.CarrierTrackingNumber)
+			if assert.Len(t, transaction.Pallets, 1) && assert.Len(t, transaction.Pallets[0].LineItems, 2) {
+				assert.Equal(t, "345345", transaction.Pallets[0].LineItems[1].ManufacturersSerialCaseNumber)
+				assert.Equal(t, "34534534", transaction.Pallets[0].LineItems[1].BuyersPurchaseOrderNumber)
+			}
+		}
+
+		standard856V7s, backReport := standard856V5.ToV7(ctx)
+		assert.Empty(t, backReport.Dropped)
+		assert.Contains(t, backReport.Defaulted, "Pallets.Shipments.TrackingID")
+		if assert.Len(t, standard856V7s, 1) {
+			assert.Equal(t, standard856V7.Transaction.ShipmentNumber, standard856V7s[0].Transaction.ShipmentNumber)
+			if assert.Len(t, standard856V7s[0].Pallets, 1) && assert.Len(t, standard856V7s[0].Pallets[0].Shipments, 1) {
+				shipment := standard856V7s[0].Pallets[0].Shipments[0]
+				assert.Equal(t, "986979879878", shipment.CarrierTrackingNumber)
+				assert.Equal(t, "79878798798", shipment.ManufacturersOrderNumber)
+				assert.Len(t, shipment.LineItems, 2)
+			}
+		}
+	}
+
+}
+
+func TestStandard856V7ToV4(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard856V7 := range Standard856V7s {
+		standard856V4, report := standard856V7.ToV4(ctx)
+		assert.Equal(t, []string{"Transaction.DeliverToContactName", "Transaction.DropShipCode"}, report.Dropped)
+		if assert.Len(t, standard856V4.Pallets, 1) && assert.Len(t, standard856V4.Pallets[0].Shipments, 1) {
+			lineItems := standard856V4.Pallets[0].Shipments[0].LineItems
+			if assert.Len(t, lineItems, 2) {
+				assert.Equal(t, 6, lineItems[1].Quantity)
+				assert.Equal(t, "345345", lineItems[1].ManufacturersSerialCaseNumber)
+			}
+		}
+
+		back, backReport := standard856V4.ToV7(ctx)
+		assert.Empty(t, backReport.Dropped)
+		assert.Equal(t, standard856V7.Pallets[0].Shipments[0].LineItems, back.Pallets[0].Shipments[0].LineItems)
+	}
+
+}
+
+func TestStandard940V2ToV1(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard940V2 := range Standard940V2s {
+		standard940V1, report := standard940V2.ToV1(ctx)
+		assert.Equal(t, standard940V2.Transaction.PurchaseOrderNumber, standard940V1.Transaction.PurchaseOrderNumber)
+		assert.Equal(t, standard940V2.Transaction.DeliverToCityName, standard940V1.Transaction.DeliverToCityName)
+		assert.Len(t, standard940V1.LineItems, len(standard940V2.LineItems))
+		assert.Len(t, standard940V1.OtherCharges, len(standard940V2.OtherCharges))
+		assert.Contains(t, report.Dropped, "Transaction.TrackingID")
+		assert.Contains(t, report.Dropped, "Transaction.CODForMerchandise")
+		assert.Contains(t, report.Dropped, "Trailer.NumberOfCases")
+		assert.NotContains(t, report.Dropped, "Transaction.ThirdPartyAccountNumber")
+		assert.Empty(t, report.Defaulted)
+
+		back, backReport := standard940V1.ToV2(ctx)
+		assert.Equal(t, standard940V2.Transaction.DeliverToPostalCode, back.Transaction.DeliverToPostalCode)
+		assert.Empty(t, back.Transaction.TrackingID)
+		assert.Empty(t, backReport.Dropped)
+		assert.Contains(t, backReport.Defaulted, "Transaction.TrackingID")
+		assert.Contains(t, backReport.Defaulted, "Trailer.PurchaseOrderTotalAmount")
+	}
+
+}
+
+func TestStandard997V2ToV3(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard997V2 := range Standard997V2s {
+		standard997V3, report := standard997V2.ToV3(ctx)
+		assert.Equal(t, standard997V2.Body.TransactionSetAcknowledgementCodes, standard997V3.Body.TransactionSetAcknowledgementCodes)
+		assert.Empty(t, standard997V3.Documents)
+		assert.Empty(t, report.Dropped)
+		assert.Empty(t, report.Defaulted)
+
+		back, backReport := standard997V3.ToV2(ctx)
+		assert.Equal(t, standard997V2.Body, back.Body)
+		assert.Empty(t, backReport.Dropped)
+		assert.Empty(t, backReport.Defaulted)
+	}
+
+	// Documents
+	standard997V3 := Standard997V3{
+		Body: Standard997V3Body{
+			TransactionSetAcknowledgementCodes: Standard997V3Rejected,
+		},
+		Documents: []Standard997V3Document{
+			Standard997V3Document{
+				TransactionType:                   "850",
+				TransactionSetAcknowledgementCode: Standard997V3Rejected,
+				Errors: []Standard997V3Error{
+					Standard997V3Error{ErrorCode: ValidationCodeParse},
+				},
+			},
+		},
+	}
+	standard997V2, report := standard997V3.ToV2(ctx)
+	assert.Equal(t, Standard997V3Rejected, standard997V2.Body.TransactionSetAcknowledgementCodes)
+	assert.Equal(t, []string{"Documents.TransactionType", "Documents.TransactionSetAcknowledgementCode", "Documents.Errors.ErrorCode"}, report.Dropped)
+	assert.Empty(t, report.Defaulted)
+
+}
@@ -0,0 +1,390 @@
+package easi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EDIFACTOptions configures a UN/EDIFACT interchange. Zero values take the
+// UNA defaults ":+.? '", a control reference of 1 and the sender and
+// recipient of the EASI envelope. LineBreak is written after every segment
+// terminator when set. ItemNumberType is the 7143 code the GTIN is written
+// with in LIN segments, EN in the D96A code list. Parsed interchanges report
+// the service string advice they were read with.
+type EDIFACTOptions struct {
+	ComponentSeparator          byte
+	ElementSeparator            byte
+	DecimalMark                 byte
+	ReleaseCharacter            byte
+	SegmentTerminator           byte
+	LineBreak                   string
+	InterchangeControlReference string
+	MessageReferenceNumber      string
+	SenderID                    string
+	SenderQualifier             string
+	RecipientID                 string
+	RecipientQualifier          string
+	TestIndicator               string
+	ItemNumberType              string
+}
+
+// EDIFACTSegment is one segment of an EDIFACT interchange, each data element
+// split into its components. Position counts segments from 1.
+type EDIFACTSegment struct {
+	Tag      string
+	Elements [][]string
+	Position int
+}
+
+type edifactWriter struct {
+	options  EDIFACTOptions
+	segments []string
+	count    int
+}
+
+func (s *EDIFACTOptions) prep(envelopeHeader EnvelopeHeaderV3) {
+
+	if s.ComponentSeparator == 0 {
+		s.ComponentSeparator = ':'
+	}
+	if s.ElementSeparator == 0 {
+		s.ElementSeparator = '+'
+	}
+	if s.DecimalMark == 0 {
+		s.DecimalMark = '.'
+	}
+	if s.ReleaseCharacter == 0 {
+		s.ReleaseCharacter = '?'
+	}
+	if s.SegmentTerminator == 0 {
+		s.SegmentTerminator = '\''
+	}
+	if s.InterchangeControlReference == "" {
+		s.InterchangeControlReference = "1"
+	}
+	if s.MessageReferenceNumber == "" {
+		s.MessageReferenceNumber = "1"
+	}
+	if s.SenderID == "" {
+		s.SenderID = envelopeHeader.SenderID
+	}
+	if s.SenderQualifier == "" {
+		s.SenderQualifier = envelopeHeader.SenderQualifier
+	}
+	if s.RecipientID == "" {
+		s.RecipientID = envelopeHeader.ReceiverID
+	}
+	if s.RecipientQualifier == "" {
+		s.RecipientQualifier = envelopeHeader.ReceiverQualifier
+	}
+	if s.TestIndicator == "" && envelopeHeader.ProductionOrTest == "T" {
+		s.TestIndicator = "1"
+	}
+	if s.ItemNumberType == "" {
+		s.ItemNumberType = "EN"
+	}
+}
+
+// Component returns a component by its position, as in 2 and 1 for the first
+// component of the second data element, or "" when the segment is shorter.
+func (s *EDIFACTSegment) Component(element int, component int) string {
+
+	if element < 1 || element > len(s.Elements) {
+		return ""
+	}
+	if component < 1 || component > len(s.Elements[element-1]) {
+		return ""
+	}
+
+	return s.Elements[element-1][component-1]
+}
+
+// header writes the UNA, UNB and UNH segments for a D96A message. Dates are
+// EASI 20060102 dates and times at least 1504.
+func (s *edifactWriter) header(messageType string, date string, time string) error {
+
+	if len(date) != 8 || len(time) < 4 {
+		return fmt.Errorf("edifact interchange date %q and time %q", date, time)
+	}
+
+	una := []byte{'U', 'N', 'A', s.options.ComponentSeparator, s.options.ElementSeparator, s.options.DecimalMark, s.options.ReleaseCharacter, ' '}
+	s.segments = append(s.segments, string(una))
+
+	var testIndicator []string
+	if s.options.TestIndicator != "" {
+		testIndicator = []string{s.options.TestIndicator}
+	}
+	s.segment([]string{"UNB"}, []string{"UNOC", "3"}, []string{s.options.SenderID, s.options.SenderQualifier}, []string{s.options.RecipientID, s.options.RecipientQualifier}, []string{date[2:], time[:4]}, []string{s.options.InterchangeControlReference}, nil, nil, nil, nil, nil, testIndicator)
+	s.count = 0
+	s.segment([]string{"UNH"}, []string{s.options.MessageReferenceNumber}, []string{messageType, "D", "96A", "UN"})
+
+	return nil
+}
+
+// trailer writes the UNT and UNZ segments for a single message.
+func (s *edifactWriter) trailer() {
+
+	s.segment([]string{"UNT"}, []string{strconv.Itoa(s.count + 1)}, []string{s.options.MessageReferenceNumber})
+	s.segment([]string{"UNZ"}, []string{"1"}, []string{s.options.InterchangeControlReference})
+}
+
+// segment writes a segment from its tag and data elements, releasing any
+// delimiter in the data and leaving out trailing empty components and
+// elements.
+func (s *edifactWriter) segment(elements ...[]string) {
+
+	var data []string
+	for _, components := range elements {
+		for len(components) > 0 && components[len(components)-1] == "" {
+			components = components[:len(components)-1]
+		}
+		var released []string
+		for _, component := range components {
+			released = append(released, s.release(component))
+		}
+		data = append(data, strings.Join(released, string(s.options.ComponentSeparator)))
+	}
+	for len(data) > 1 && data[len(data)-1] == "" {
+		data = data[:len(data)-1]
+	}
+
+	s.segments = append(s.segments, strings.Join(data, string(s.options.ElementSeparator)))
+	s.count++
+}
+
+func (s *edifactWriter) release(component string) string {
+
+	var released strings.Builder
+	for i := 0; i < len(component); i++ {
+		switch component[i] {
+		case s.options.ComponentSeparator, s.options.ElementSeparator, s.options.ReleaseCharacter, s.options.SegmentTerminator:
+			released.WriteByte(s.options.ReleaseCharacter)
+		}
+		released.WriteByte(component[i])
+	}
+
+	return released.String()
+}
+
+func (s *edifactWriter) bytes() *[]byte {
+
+	terminator := string(s.options.SegmentTerminator) + s.options.LineBreak
+	byteArray := []byte(strings.Join(s.segments, terminator) + terminator)
+
+	return &byteArray
+}
+
+func (s *edifactWriter) amount(amount int) string {
+
+	return strings.Replace(x12Amount(amount), ".", string(s.options.DecimalMark), 1)
+}
+
+// ParseEDIFACT splits an interchange into segments, taking the delimiters from
+// its UNA service string advice when there is one. Released delimiters are
+// read as data, and line breaks between segments are ignored. The UNA itself
+// is not returned as a segment.
+func ParseEDIFACT(ctx context.Context, req []byte) ([]EDIFACTSegment, EDIFACTOptions, error) {
+
+	options := EDIFACTOptions{
+		ComponentSeparator: ':',
+		ElementSeparator:   '+',
+		DecimalMark:        '.',
+		ReleaseCharacter:   '?',
+		SegmentTerminator:  '\'',
+	}
+
+	data := strings.TrimLeft(string(req), " \t\r\n")
+	if strings.HasPrefix(data, "UNA") {
+		if len(data) < 9 {
+			return nil, options, fmt.Errorf("edifact UNA segment is too short")
+		}
+		options.ComponentSeparator = data[3]
+		options.ElementSeparator = data[4]
+		options.DecimalMark = data[5]
+		options.ReleaseCharacter = data[6]
+		options.SegmentTerminator = data[8]
+		data = data[9:]
+	}
+
+	var segments []EDIFACTSegment
+	var elements [][]string
+	var component strings.Builder
+	started := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == options.ReleaseCharacter:
+			if i+1 >= len(data) {
+				return nil, options, fmt.Errorf("edifact release character at the end of the interchange")
+			}
+			i++
+			component.WriteByte(data[i])
+			started = true
+		case c == options.ComponentSeparator:
+			if len(elements) == 0 {
+				elements = append(elements, []string{})
+			}
+			elements[len(elements)-1] = append(elements[len(elements)-1], component.String())
+			component.Reset()
+		case c == options.ElementSeparator:
+			if len(elements) == 0 {
+				elements = append(elements, []string{})
+			}
+			elements[len(elements)-1] = append(elements[len(elements)-1], component.String())
+			component.Reset()
+			elements = append(elements, []string{})
+		case c == options.SegmentTerminator:
+			if len(elements) == 0 {
+				elements = append(elements, []string{})
+			}
+			elements[len(elements)-1] = append(elements[len(elements)-1], component.String())
+			component.Reset()
+			segments = append(segments, EDIFACTSegment{
+				Tag:      elements[0][0],
+				Elements: elements[1:],
+				Position: len(segments) + 1,
+			})
+			elements = nil
+			started = false
+		case (c == '\r' || c == '\n') && !started:
+		default:
+			component.WriteByte(c)
+			started = true
+		}
+	}
+	if started || len(elements) > 0 {
+		return nil, options, fmt.Errorf("edifact interchange ends without a segment terminator")
+	}
+
+	// Interchange
+	if len(segments) == 0 || segments[0].Tag != "UNB" {
+		return nil, options, fmt.Errorf("edifact interchange does not start with a UNB segment")
+	}
+	unb := segments[0]
+	options.SenderID = unb.Component(2, 1)
+	options.SenderQualifier = unb.Component(2, 2)
+	options.RecipientID = unb.Component(3, 1)
+	options.RecipientQualifier = unb.Component(3, 2)
+	options.InterchangeControlReference = unb.Component(5, 1)
+	options.TestIndicator = unb.Component(11, 1)
+
+	return segments, options, nil
+}
+
+// edifactAmount reads a decimal written with the decimal mark of the
+// interchange as cents.
+func edifactAmount(options EDIFACTOptions, req string) (int, error) {
+
+	return parseAmount(strings.Replace(req, string(options.DecimalMark), ".", 1))
+}
+
+// edifactPurposes maps EASI transaction set purpose codes to EDIFACT message
+// function codes.
+var edifactPurposes = map[string]string{
+	"00": "9",
+	"01": "1",
+	"05": "5",
+}
+
+func edifactPurpose(transactionSetPurpose string) string {
+
+	if messageFunction, ok := edifactPurposes[transactionSetPurpose]; ok {
+		return messageFunction
+	}
+
+	return "9"
+}
+
+func edifactTransactionSetPurpose(messageFunction string) string {
+
+	for transactionSetPurpose, code := range edifactPurposes {
+		if code == messageFunction {
+			return transactionSetPurpose
+		}
+	}
+
+	return "00"
+}
+
+// edifactParties are the NAD parties of an ORDERS or DESADV message: the ship
+// to party with its address and delivery contact, and the UC, BY and SU
+// parties identified by their buyer assigned IDs.
+type edifactParties struct {
+	DistributionCenterID string
+	StoreID              string
+	PurchaserAccountID   string
+	VendorID             string
+	ShipTo               Address
+	party                string
+}
+
+func (s *edifactParties) write(w *edifactWriter) {
+
+	var codeListResponsibleAgency string
+	if s.DistributionCenterID != "" {
+		codeListResponsibleAgency = "92"
+	}
+	w.segment([]string{"NAD"}, []string{"ST"}, []string{s.DistributionCenterID, "", codeListResponsibleAgency}, nil, []string{s.ShipTo.CompanyName}, []string{s.ShipTo.Address1, s.ShipTo.Address2}, []string{s.ShipTo.CityName}, []string{s.ShipTo.StateCode}, []string{s.ShipTo.PostalCode}, []string{s.ShipTo.CountryCode})
+	if s.ShipTo.ContactName != "" {
+		w.segment([]string{"CTA"}, []string{"DL"}, []string{"", s.ShipTo.ContactName})
+	}
+	if s.StoreID != "" {
+		w.segment([]string{"NAD"}, []string{"UC"}, []string{s.StoreID, "", "92"})
+	}
+	if s.PurchaserAccountID != "" {
+		w.segment([]string{"NAD"}, []string{"BY"}, []string{s.PurchaserAccountID, "", "92"})
+	}
+	if s.VendorID != "" {
+		w.segment([]string{"NAD"}, []string{"SU"}, []string{s.VendorID, "", "92"})
+	}
+}
+
+// read takes a NAD segment, or a CTA segment of the ship to party, and
+// reports false for any other party.
+func (s *edifactParties) read(segment EDIFACTSegment) bool {
+
+	if segment.Tag == "CTA" {
+		if s.party != "ST" || segment.Component(1, 1) != "DL" {
+			return false
+		}
+		s.ShipTo.ContactName = segment.Component(2, 2)
+		return true
+	}
+
+	s.party = segment.Component(1, 1)
+	switch s.party {
+	case "ST":
+		s.DistributionCenterID = segment.Component(2, 1)
+		s.ShipTo.CompanyName = segment.Component(4, 1)
+		s.ShipTo.Address1 = segment.Component(5, 1)
+		s.ShipTo.Address2 = segment.Component(5, 2)
+		s.ShipTo.CityName = segment.Component(6, 1)
+		s.ShipTo.StateCode = segment.Component(7, 1)
+		s.ShipTo.PostalCode = segment.Component(8, 1)
+		s.ShipTo.CountryCode = segment.Component(9, 1)
+	case "UC":
+		s.StoreID = segment.Component(2, 1)
+	case "BY":
+		s.PurchaserAccountID = segment.Component(2, 1)
+	case "SU":
+		s.VendorID = segment.Component(2, 1)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// edifactGTIN reads the GTIN of a LIN segment. Item number types other than
+// the D96A EN, or the SRV of later directories, are not read as a GTIN.
+func edifactGTIN(segment EDIFACTSegment) (string, bool) {
+
+	switch segment.Component(3, 2) {
+	case "EN", "SRV":
+		return segment.Component(3, 1), true
+	}
+
+	return "", segment.Component(3, 1) == ""
+}
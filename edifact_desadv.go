@@ -0,0 +1,368 @@
+package easi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ToEDIFACT renders the ship notice as an EDIFACT D96A DESADV message. The
+// consignment is CPS 1, each pallet a CPS under it and each shipment a CPS
+// under its pallet, with pallet IDs and case numbers as GIN BJ SSCCs. Weights
+// are in pounds.
+func (s *Standard856V7) ToEDIFACT(ctx context.Context, options EDIFACTOptions) (*[]byte, error) {
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+	options.prep(s.EnvelopeHeaderV3)
+
+	w := edifactWriter{options: options}
+	errHeader := w.header("DESADV", s.EnvelopeHeaderV3.FileCreationDate, s.EnvelopeHeaderV3.FileCreationTime)
+	if errHeader != nil {
+		return nil, errHeader
+	}
+
+	// Beginning Of Message
+	w.segment([]string{"BGM"}, []string{"351"}, []string{s.Transaction.ShipmentNumber}, []string{edifactPurpose(s.Transaction.TransactionSetPurpose)})
+
+	// Dates
+	if s.Transaction.ASNDate != "" {
+		w.segment([]string{"DTM"}, edifactDateTime("137", s.Transaction.ASNDate, s.Transaction.ASNTime))
+	}
+	if s.Transaction.ShipmentDate != "" {
+		w.segment([]string{"DTM"}, []string{"11", s.Transaction.ShipmentDate, "102"})
+	}
+
+	// References
+	if s.Transaction.BOLNumber != "" {
+		w.segment([]string{"RFF"}, []string{"BM", s.Transaction.BOLNumber})
+	}
+
+	// Parties
+	parties := edifactParties{
+		DistributionCenterID: s.Transaction.DistributionCenterID,
+		StoreID:              s.Transaction.StoreID,
+		PurchaserAccountID:   s.Transaction.PurchaserAccountID,
+		VendorID:             s.Transaction.VendorID,
+		ShipTo: Address{
+			CompanyName: s.Transaction.DeliverToCompanyName,
+			ContactName: s.Transaction.DeliverToContactName,
+			Address1:    s.Transaction.DeliverToAddress1,
+			Address2:    s.Transaction.DeliverToAddress2,
+			CityName:    s.Transaction.DeliverToCityName,
+			StateCode:   s.Transaction.DeliverToStateCode,
+			PostalCode:  s.Transaction.DeliverToPostalCode,
+			CountryCode: s.Transaction.DeliverToCountryCode,
+		},
+	}
+	parties.write(&w)
+
+	// Carrier
+	if s.Transaction.CarrierRoutingDetails != "" {
+		w.segment([]string{"TDT"}, []string{"20"}, nil, nil, nil, []string{"", "", "", s.Transaction.CarrierRoutingDetails})
+	}
+	if s.Transaction.TrailerID != "" {
+		w.segment([]string{"EQD"}, []string{"TE"}, []string{s.Transaction.TrailerID})
+	}
+
+	// Consignment
+	w.segment([]string{"CPS"}, []string{"1"})
+
+	var lineItemCount int
+	hierarchicalID := 1
+	for _, pallet := range s.Pallets {
+
+		// Pallet
+		hierarchicalID++
+		palletID := hierarchicalID
+		w.segment([]string{"CPS"}, []string{strconv.Itoa(palletID)}, []string{"1"})
+		w.segment([]string{"PAC"}, []string{"1"}, nil, []string{"PX"})
+		if pallet.PalletID != "" {
+			w.segment([]string{"GIN"}, []string{"BJ"}, []string{pallet.PalletID})
+		}
+
+		for _, shipment := range pallet.Shipments {
+
+			// Shipment
+			hierarchicalID++
+			w.segment([]string{"CPS"}, []string{strconv.Itoa(hierarchicalID)}, []string{strconv.Itoa(palletID)})
+			w.segment([]string{"PAC"}, []string{"1"}, nil, []string{"CT"})
+			if shipment.CaseWeight > 0 {
+				w.segment([]string{"MEA"}, []string{"PD"}, []string{"AAB"}, []string{"LBR", strings.Replace(x12Weight(shipment.CaseWeight), ".", string(w.options.DecimalMark), 1)})
+			}
+			if shipment.ManufacturersSerialCaseNumber != "" {
+				w.segment([]string{"GIN"}, []string{"BJ"}, []string{shipment.ManufacturersSerialCaseNumber})
+			}
+			if shipment.FreightCharge != 0 {
+				w.segment([]string{"MOA"}, []string{"64", w.amount(shipment.FreightCharge)})
+			}
+			if shipment.BuyersPurchaseOrderNumber != "" {
+				w.segment([]string{"RFF"}, []string{"ON", shipment.BuyersPurchaseOrderNumber})
+				if shipment.PODate != "" {
+					w.segment([]string{"DTM"}, edifactDateTime("171", shipment.PODate, shipment.POTime))
+				}
+			}
+			if shipment.ManufacturersOrderNumber != "" {
+				w.segment([]string{"RFF"}, []string{"VN", shipment.ManufacturersOrderNumber})
+			}
+			if shipment.CarrierTrackingNumber != "" {
+				w.segment([]string{"RFF"}, []string{"CN", shipment.CarrierTrackingNumber})
+			}
+
+			for _, lineItem := range shipment.LineItems {
+
+				// Line Item
+				lineItemCount++
+				lineItemNumber := lineItem.LineItemNumber
+				if lineItemNumber <= 0 {
+					lineItemNumber = lineItemCount
+				}
+				w.segment([]string{"LIN"}, []string{strconv.Itoa(lineItemNumber)}, nil, []string{lineItem.ItemIdentificationGTIN, w.options.ItemNumberType})
+				if lineItem.MasterStyle != "" || lineItem.DetailStyle != "" {
+					w.segment([]string{"PIA"}, []string{"1"}, []string{lineItem.MasterStyle, "VN"}, []string{lineItem.DetailStyle, "SA"})
+				}
+				if lineItem.ColorCode != "" {
+					w.segment([]string{"IMD"}, []string{"F"}, []string{"35"}, []string{"", "", "", lineItem.ColorCode})
+				}
+				if lineItem.SizeCode != "" {
+					w.segment([]string{"IMD"}, []string{"F"}, []string{"98"}, []string{"", "", "", lineItem.SizeCode})
+				}
+				w.segment([]string{"QTY"}, []string{"12", strconv.Itoa(lineItem.QuantityShipped), lineItem.UnitOrBasisForMeasurementCode})
+				if lineItem.CountryOfOrigin != "" {
+					w.segment([]string{"ALI"}, []string{lineItem.CountryOfOrigin})
+				}
+				if lineItem.ManufacturersLotID != "" {
+					w.segment([]string{"GIN"}, []string{"BX"}, []string{lineItem.ManufacturersLotID})
+				}
+				if lineItem.BuyersPurchaseOrderNumber != "" {
+					w.segment([]string{"RFF"}, []string{"ON", lineItem.BuyersPurchaseOrderNumber})
+				}
+			}
+		}
+	}
+
+	// Summary
+	w.segment([]string{"CNT"}, []string{"2", strconv.Itoa(lineItemCount)})
+
+	w.trailer()
+
+	return w.bytes(), nil
+}
+
+// FromEDIFACT reads an EDIFACT DESADV interchange holding one ship notice,
+// the reverse of ToEDIFACT. Packages are read as pallets when they sit under
+// the consignment and as shipments when they sit under a pallet. The segments
+// it has no field for are returned rather than dropped.
+func (s *Standard856V7) FromEDIFACT(ctx context.Context, req []byte) ([]EDIFACTSegment, error) {
+
+	segments, options, err := ParseEDIFACT(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Envelope
+	s.EnvelopeHeaderV3.SenderID = options.SenderID
+	s.EnvelopeHeaderV3.SenderQualifier = options.SenderQualifier
+	s.EnvelopeHeaderV3.ReceiverID = options.RecipientID
+	s.EnvelopeHeaderV3.ReceiverQualifier = options.RecipientQualifier
+	s.EnvelopeHeaderV3.InterchangeID = options.InterchangeControlReference
+	s.EnvelopeTrailerV3.InterchangeID = options.InterchangeControlReference
+	if options.TestIndicator == "1" {
+		s.EnvelopeHeaderV3.ProductionOrTest = "T"
+	} else {
+		s.EnvelopeHeaderV3.ProductionOrTest = "P"
+	}
+
+	var unmapped []EDIFACTSegment
+	var messages int
+	var parties edifactParties
+	var level, reference string
+	consignments := map[string]bool{}
+	pallets := map[string]int{}
+	for _, segment := range segments {
+
+		var pallet *Standard856V7Pallet
+		var shipment *Standard856V7Shipment
+		var lineItem *Standard856V7LineItem
+		if len(s.Pallets) > 0 {
+			pallet = &s.Pallets[len(s.Pallets)-1]
+			if len(pallet.Shipments) > 0 {
+				shipment = &pallet.Shipments[len(pallet.Shipments)-1]
+				if len(shipment.LineItems) > 0 {
+					lineItem = &shipment.LineItems[len(shipment.LineItems)-1]
+				}
+			}
+		}
+
+		mapped := true
+		switch {
+		case segment.Tag == "UNB" || segment.Tag == "UNT" || segment.Tag == "UNZ":
+		case segment.Tag == "UNH":
+			if segment.Component(2, 1) != "DESADV" {
+				return nil, fmt.Errorf("edifact message %s at segment %d is not a DESADV", segment.Component(2, 1), segment.Position)
+			}
+			messages++
+			if messages > 1 {
+				return nil, fmt.Errorf("edifact interchange holds more than one DESADV")
+			}
+		case segment.Tag == "BGM":
+			s.Transaction.ShipmentNumber = segment.Component(2, 1)
+			s.Transaction.TransactionSetPurpose = edifactTransactionSetPurpose(segment.Component(3, 1))
+		case segment.Tag == "NAD" || segment.Tag == "CTA":
+			mapped = level == "" && parties.read(segment)
+		case segment.Tag == "TDT" && level == "":
+			s.Transaction.CarrierRoutingDetails = segment.Component(5, 4)
+		case segment.Tag == "EQD" && level == "" && segment.Component(1, 1) == "TE":
+			s.Transaction.TrailerID = segment.Component(2, 1)
+		case segment.Tag == "CPS":
+			parent := segment.Component(2, 1)
+			palletKey, parentIsPallet := pallets[parent]
+			switch {
+			case parent == "":
+				level = "consignment"
+				consignments[segment.Component(1, 1)] = true
+			case consignments[parent]:
+				level = "pallet"
+				pallets[segment.Component(1, 1)] = len(s.Pallets)
+				s.Pallets = append(s.Pallets, Standard856V7Pallet{})
+			case parentIsPallet && palletKey == len(s.Pallets)-1:
+				level = "shipment"
+				pallet.Shipments = append(pallet.Shipments, Standard856V7Shipment{})
+			default:
+				return nil, fmt.Errorf("edifact CPS at segment %d is not a pallet of the consignment or a shipment of the last pallet", segment.Position)
+			}
+			reference = ""
+		case segment.Tag == "PAC":
+			mapped = level == "pallet" || level == "shipment"
+		case segment.Tag == "GIN":
+			switch {
+			case level == "pallet" && segment.Component(1, 1) == "BJ":
+				pallet.PalletID = segment.Component(2, 1)
+			case level == "shipment" && segment.Component(1, 1) == "BJ":
+				shipment.ManufacturersSerialCaseNumber = segment.Component(2, 1)
+			case level == "line" && segment.Component(1, 1) == "BX":
+				lineItem.ManufacturersLotID = segment.Component(2, 1)
+			default:
+				mapped = false
+			}
+		case segment.Tag == "MEA" && level == "shipment" && segment.Component(2, 1) == "AAB":
+			caseWeight, err := strconv.ParseFloat(strings.Replace(segment.Component(3, 2), string(options.DecimalMark), ".", 1), 64)
+			if err != nil {
+				return nil, fmt.Errorf("edifact MEA at segment %d: %w", segment.Position, err)
+			}
+			shipment.CaseWeight = caseWeight
+		case segment.Tag == "MOA" && level == "shipment" && segment.Component(1, 1) == "64":
+			freightCharge, err := edifactAmount(options, segment.Component(1, 2))
+			if err != nil {
+				return nil, fmt.Errorf("edifact MOA at segment %d: %w", segment.Position, err)
+			}
+			shipment.FreightCharge = freightCharge
+		case segment.Tag == "RFF":
+			reference = level + segment.Component(1, 1)
+			switch reference {
+			case "BM":
+				s.Transaction.BOLNumber = segment.Component(1, 2)
+			case "shipmentON":
+				shipment.BuyersPurchaseOrderNumber = segment.Component(1, 2)
+			case "shipmentVN":
+				shipment.ManufacturersOrderNumber = segment.Component(1, 2)
+			case "shipmentCN":
+				shipment.CarrierTrackingNumber = segment.Component(1, 2)
+			case "lineON":
+				lineItem.BuyersPurchaseOrderNumber = segment.Component(1, 2)
+			default:
+				mapped = false
+			}
+		case segment.Tag == "DTM":
+			date, time := edifactDate(segment)
+			switch {
+			case level == "" && segment.Component(1, 1) == "137":
+				s.Transaction.ASNDate, s.Transaction.ASNTime = date, time
+			case level == "" && segment.Component(1, 1) == "11":
+				s.Transaction.ShipmentDate = date
+			case reference == "shipmentON" && segment.Component(1, 1) == "171":
+				shipment.PODate, shipment.POTime = date, time
+			default:
+				mapped = false
+			}
+		case segment.Tag == "LIN" && (level == "shipment" || level == "line"):
+			level = "line"
+			var x Standard856V7LineItem
+			x.LineItemNumber, _ = strconv.Atoi(segment.Component(1, 1))
+			x.ItemIdentificationGTIN, mapped = edifactGTIN(segment)
+			shipment.LineItems = append(shipment.LineItems, x)
+		case level == "line" && segment.Tag == "PIA" && segment.Component(2, 2) == "VN":
+			lineItem.MasterStyle = segment.Component(2, 1)
+			if segment.Component(3, 2) == "SA" {
+				lineItem.DetailStyle = segment.Component(3, 1)
+			}
+		case level == "line" && segment.Tag == "IMD" && segment.Component(2, 1) == "35":
+			lineItem.ColorCode = segment.Component(3, 4)
+		case level == "line" && segment.Tag == "IMD" && segment.Component(2, 1) == "98":
+			lineItem.SizeCode = segment.Component(3, 4)
+		case level == "line" && segment.Tag == "QTY" && segment.Component(1, 1) == "12":
+			quantityShipped, err := strconv.ParseFloat(segment.Component(1, 2), 64)
+			if err != nil {
+				return nil, fmt.Errorf("edifact QTY at segment %d: %w", segment.Position, err)
+			}
+			lineItem.QuantityShipped = int(math.Round(quantityShipped))
+			lineItem.UnitOrBasisForMeasurementCode = segment.Component(1, 3)
+		case level == "line" && segment.Tag == "ALI":
+			lineItem.CountryOfOrigin = segment.Component(1, 1)
+		case segment.Tag == "CNT" && segment.Component(1, 1) == "2":
+		default:
+			mapped = false
+		}
+		if !mapped {
+			unmapped = append(unmapped, segment)
+		}
+	}
+	if messages == 0 {
+		return nil, fmt.Errorf("edifact interchange holds no DESADV")
+	}
+
+	// Parties
+	s.Transaction.DistributionCenterID = parties.DistributionCenterID
+	s.Transaction.StoreID = parties.StoreID
+	s.Transaction.PurchaserAccountID = parties.PurchaserAccountID
+	s.Transaction.VendorID = parties.VendorID
+	s.Transaction.DeliverToCompanyName = parties.ShipTo.CompanyName
+	s.Transaction.DeliverToContactName = parties.ShipTo.ContactName
+	s.Transaction.DeliverToAddress1 = parties.ShipTo.Address1
+	s.Transaction.DeliverToAddress2 = parties.ShipTo.Address2
+	s.Transaction.DeliverToCityName = parties.ShipTo.CityName
+	s.Transaction.DeliverToStateCode = parties.ShipTo.StateCode
+	s.Transaction.DeliverToPostalCode = parties.ShipTo.PostalCode
+	s.Transaction.DeliverToCountryCode = parties.ShipTo.CountryCode
+
+	return unmapped, nil
+}
+
+// edifactDateTime writes a DTM period with the time when there is one.
+func edifactDateTime(qualifier string, date string, time string) []string {
+
+	switch len(time) {
+	case 6:
+		return []string{qualifier, date + time, "204"}
+	case 4:
+		return []string{qualifier, date + time, "203"}
+	}
+
+	return []string{qualifier, date, "102"}
+}
+
+// edifactDate reads the EASI date and time of a DTM period.
+func edifactDate(segment EDIFACTSegment) (string, string) {
+
+	period := segment.Component(1, 2)
+	if len(period) <= 8 {
+		return period, ""
+	}
+
+	return period[:8], period[8:]
+}
@@ -0,0 +1,304 @@
+package easi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ToEDIFACT renders the purchase order as an EDIFACT D96A ORDERS message. The
+// distribution center is the ST party, the store the UC party, the purchaser
+// the BY party and the vendor the SU party. Other charges become ALC groups
+// with their amount in a MOA, and color and size IMD segments of their line.
+// The purchase order type is the BGM document name, the release number goes
+// with the order number in an RFF+ON, the routing is a TDT and the special
+// instructions are FTX segments. Payment terms, sales requirements and the
+// parcel and COD fields have no segment here and are not written.
+func (s *Standard850V4) ToEDIFACT(ctx context.Context, options EDIFACTOptions) (*[]byte, error) {
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+	options.prep(s.EnvelopeHeaderV3)
+
+	w := edifactWriter{options: options}
+	errHeader := w.header("ORDERS", s.EnvelopeHeaderV3.FileCreationDate, s.EnvelopeHeaderV3.FileCreationTime)
+	if errHeader != nil {
+		return nil, errHeader
+	}
+
+	// Beginning Of Message
+	w.segment([]string{"BGM"}, []string{"220", "", "", s.Transaction.PurchaseOrderTypeCode}, []string{s.Transaction.PurchaseOrderNumber}, []string{edifactPurpose(s.Transaction.TransactionSetPurpose)})
+
+	// Dates
+	switch {
+	case s.Transaction.PODate != "" && len(s.Transaction.POTime) == 6:
+		w.segment([]string{"DTM"}, []string{"137", s.Transaction.PODate + s.Transaction.POTime, "204"})
+	case s.Transaction.PODate != "" && len(s.Transaction.POTime) == 4:
+		w.segment([]string{"DTM"}, []string{"137", s.Transaction.PODate + s.Transaction.POTime, "203"})
+	case s.Transaction.PODate != "":
+		w.segment([]string{"DTM"}, []string{"137", s.Transaction.PODate, "102"})
+	}
+	if s.Transaction.RequestedShipDate != "" {
+		w.segment([]string{"DTM"}, []string{"10", s.Transaction.RequestedShipDate, "102"})
+	}
+	if s.Transaction.CancelDate != "" {
+		w.segment([]string{"DTM"}, []string{"61", s.Transaction.CancelDate, "102"})
+	}
+
+	// Free Text
+	if s.Transaction.SpecialDeliveryInstructions != "" {
+		w.segment([]string{"FTX"}, []string{"DEL"}, nil, nil, []string{s.Transaction.SpecialDeliveryInstructions})
+	}
+	if s.Transaction.SpecialOrderInstructions != "" {
+		w.segment([]string{"FTX"}, []string{"PUR"}, nil, nil, []string{s.Transaction.SpecialOrderInstructions})
+	}
+
+	// References
+	if s.Transaction.ReleaseNumber != "" {
+		w.segment([]string{"RFF"}, []string{"ON", s.Transaction.PurchaseOrderNumber, s.Transaction.ReleaseNumber})
+	}
+	if s.Transaction.ContractNumber != "" {
+		w.segment([]string{"RFF"}, []string{"CT", s.Transaction.ContractNumber})
+	}
+	if s.Transaction.CustomerPONumber != "" {
+		w.segment([]string{"RFF"}, []string{"CO", s.Transaction.CustomerPONumber})
+	}
+
+	// Parties
+	parties := edifactParties{
+		DistributionCenterID: s.Transaction.DistributionCenterID,
+		StoreID:              s.Transaction.StoreID,
+		PurchaserAccountID:   s.Transaction.PurchaserAccountID,
+		VendorID:             s.Transaction.VendorID,
+		ShipTo: Address{
+			CompanyName: s.Transaction.DeliverToCompanyName,
+			ContactName: s.Transaction.DeliverToContactName,
+			Address1:    s.Transaction.DeliverToAddress1,
+			Address2:    s.Transaction.DeliverToAddress2,
+			CityName:    s.Transaction.DeliverToCityName,
+			StateCode:   s.Transaction.DeliverToStateCode,
+			PostalCode:  s.Transaction.DeliverToPostalCode,
+			CountryCode: s.Transaction.DeliverToCountryCode,
+		},
+	}
+	parties.write(&w)
+
+	// Currency
+	if s.Transaction.CurrencyCode != "" {
+		w.segment([]string{"CUX"}, []string{"2", s.Transaction.CurrencyCode, "9"})
+	}
+
+	// Routing
+	if s.Transaction.CarrierRoutingDetails != "" {
+		w.segment([]string{"TDT"}, []string{"20"}, nil, nil, nil, []string{"", "", "", s.Transaction.CarrierRoutingDetails})
+	}
+
+	// Other Charges
+	for _, otherCharge := range s.OtherCharges {
+		w.segment([]string{"ALC"}, []string{"C"}, nil, nil, nil, []string{"", "", "", otherCharge.OtherChargeDescription})
+		w.segment([]string{"MOA"}, []string{"23", w.amount(otherCharge.OtherChargeAmount)})
+	}
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		w.segment([]string{"LIN"}, []string{strconv.Itoa(lineItem.LineItemNumber)}, nil, []string{lineItem.ItemIdentificationGTIN, w.options.ItemNumberType})
+		if lineItem.MasterStyle != "" {
+			w.segment([]string{"PIA"}, []string{"1"}, []string{lineItem.MasterStyle, "VN"})
+		}
+		if lineItem.ColorCode != "" {
+			w.segment([]string{"IMD"}, []string{"F"}, []string{"35"}, []string{"", "", "", lineItem.ColorCode})
+		}
+		if lineItem.SizeCode != "" {
+			w.segment([]string{"IMD"}, []string{"F"}, []string{"98"}, []string{"", "", "", lineItem.SizeCode})
+		}
+		w.segment([]string{"QTY"}, []string{"21", strconv.Itoa(lineItem.QuantityOrdered), lineItem.UnitOrBasisForMeasurementCode})
+		w.segment([]string{"PRI"}, []string{"AAA", w.amount(lineItem.PurchaseUnitPrice)})
+	}
+
+	// Summary
+	w.segment([]string{"UNS"}, []string{"S"})
+	w.segment([]string{"CNT"}, []string{"1", strconv.Itoa(s.Trailer.TotalQuantityOrdered)})
+	w.segment([]string{"CNT"}, []string{"2", strconv.Itoa(len(s.LineItems))})
+
+	w.trailer()
+
+	return w.bytes(), nil
+}
+
+// FromEDIFACT reads an EDIFACT ORDERS interchange holding one purchase order,
+// the reverse of ToEDIFACT. The segments it has no field for are returned
+// rather than dropped, as are allowances and charges of a line.
+func (s *Standard850V4) FromEDIFACT(ctx context.Context, req []byte) ([]EDIFACTSegment, error) {
+
+	segments, options, err := ParseEDIFACT(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Envelope
+	s.EnvelopeHeaderV3.SenderID = options.SenderID
+	s.EnvelopeHeaderV3.SenderQualifier = options.SenderQualifier
+	s.EnvelopeHeaderV3.ReceiverID = options.RecipientID
+	s.EnvelopeHeaderV3.ReceiverQualifier = options.RecipientQualifier
+	s.EnvelopeHeaderV3.InterchangeID = options.InterchangeControlReference
+	s.EnvelopeTrailerV3.InterchangeID = options.InterchangeControlReference
+	if options.TestIndicator == "1" {
+		s.EnvelopeHeaderV3.ProductionOrTest = "T"
+	} else {
+		s.EnvelopeHeaderV3.ProductionOrTest = "P"
+	}
+
+	var unmapped []EDIFACTSegment
+	var messages int
+	var parties edifactParties
+	for _, segment := range segments {
+		mapped := true
+		switch segment.Tag {
+		case "UNB", "UNT", "UNZ", "UNS":
+		case "UNH":
+			if segment.Component(2, 1) != "ORDERS" {
+				return nil, fmt.Errorf("edifact message %s at segment %d is not an ORDERS", segment.Component(2, 1), segment.Position)
+			}
+			messages++
+			if messages > 1 {
+				return nil, fmt.Errorf("edifact interchange holds more than one ORDERS")
+			}
+		case "BGM":
+			s.Transaction.PurchaseOrderTypeCode = segment.Component(1, 4)
+			s.Transaction.PurchaseOrderNumber = segment.Component(2, 1)
+			s.Transaction.TransactionSetPurpose = edifactTransactionSetPurpose(segment.Component(3, 1))
+		case "DTM":
+			switch segment.Component(1, 1) {
+			case "137":
+				s.Transaction.PODate = segment.Component(1, 2)
+				if len(s.Transaction.PODate) > 8 {
+					s.Transaction.POTime = s.Transaction.PODate[8:]
+					s.Transaction.PODate = s.Transaction.PODate[:8]
+				}
+			case "10":
+				s.Transaction.RequestedShipDate = segment.Component(1, 2)
+			case "61":
+				s.Transaction.CancelDate = segment.Component(1, 2)
+			default:
+				mapped = false
+			}
+		case "FTX":
+			switch segment.Component(1, 1) {
+			case "DEL":
+				s.Transaction.SpecialDeliveryInstructions = segment.Component(4, 1)
+			case "PUR":
+				s.Transaction.SpecialOrderInstructions = segment.Component(4, 1)
+			default:
+				mapped = false
+			}
+		case "RFF":
+			switch segment.Component(1, 1) {
+			case "ON":
+				s.Transaction.ReleaseNumber = segment.Component(1, 3)
+			case "CT":
+				s.Transaction.ContractNumber = segment.Component(1, 2)
+			case "CO":
+				s.Transaction.CustomerPONumber = segment.Component(1, 2)
+			default:
+				mapped = false
+			}
+		case "NAD", "CTA":
+			mapped = parties.read(segment)
+		case "CUX":
+			s.Transaction.CurrencyCode = segment.Component(1, 2)
+		case "TDT":
+			mapped = segment.Component(1, 1) == "20"
+			if mapped {
+				s.Transaction.CarrierRoutingDetails = segment.Component(5, 4)
+			}
+		case "ALC":
+			mapped = segment.Component(1, 1) == "C" && len(s.LineItems) == 0
+			if mapped {
+				s.OtherCharges = append(s.OtherCharges, Standard850V4OtherCharge{
+					LineItemNumberForOtherCharges: len(s.OtherCharges) + 1,
+					OtherChargeDescription:        segment.Component(5, 4),
+				})
+			}
+		case "MOA":
+			mapped = segment.Component(1, 1) == "23" && len(s.OtherCharges) > 0 && len(s.LineItems) == 0
+			if mapped {
+				amount, err := edifactAmount(options, segment.Component(1, 2))
+				if err != nil {
+					return nil, fmt.Errorf("edifact MOA at segment %d: %w", segment.Position, err)
+				}
+				s.OtherCharges[len(s.OtherCharges)-1].OtherChargeAmount = amount
+			}
+		case "LIN":
+			var x Standard850V4LineItem
+			x.LineItemNumber, _ = strconv.Atoi(segment.Component(1, 1))
+			x.ItemIdentificationGTIN, mapped = edifactGTIN(segment)
+			s.LineItems = append(s.LineItems, x)
+		case "PIA", "IMD", "QTY", "PRI":
+			mapped = len(s.LineItems) > 0
+			if !mapped {
+				break
+			}
+			lineItem := &s.LineItems[len(s.LineItems)-1]
+			switch {
+			case segment.Tag == "PIA" && segment.Component(2, 2) == "VN":
+				lineItem.MasterStyle = segment.Component(2, 1)
+			case segment.Tag == "IMD" && segment.Component(2, 1) == "35":
+				lineItem.ColorCode = segment.Component(3, 4)
+			case segment.Tag == "IMD" && segment.Component(2, 1) == "98":
+				lineItem.SizeCode = segment.Component(3, 4)
+			case segment.Tag == "QTY" && segment.Component(1, 1) == "21":
+				quantityOrdered, err := strconv.ParseFloat(segment.Component(1, 2), 64)
+				if err != nil {
+					return nil, fmt.Errorf("edifact QTY at segment %d: %w", segment.Position, err)
+				}
+				lineItem.QuantityOrdered = int(math.Round(quantityOrdered))
+				lineItem.UnitOrBasisForMeasurementCode = segment.Component(1, 3)
+			case segment.Tag == "PRI" && segment.Component(1, 1) == "AAA":
+				purchaseUnitPrice, err := edifactAmount(options, segment.Component(1, 2))
+				if err != nil {
+					return nil, fmt.Errorf("edifact PRI at segment %d: %w", segment.Position, err)
+				}
+				lineItem.PurchaseUnitPrice = purchaseUnitPrice
+			default:
+				mapped = false
+			}
+		case "CNT":
+			switch segment.Component(1, 1) {
+			case "1":
+				s.Trailer.TotalQuantityOrdered, _ = strconv.Atoi(segment.Component(1, 2))
+			case "2":
+				s.Trailer.RecordCount, _ = strconv.Atoi(segment.Component(1, 2))
+			default:
+				mapped = false
+			}
+		default:
+			mapped = false
+		}
+		if !mapped {
+			unmapped = append(unmapped, segment)
+		}
+	}
+	if messages == 0 {
+		return nil, fmt.Errorf("edifact interchange holds no ORDERS")
+	}
+
+	// Parties
+	s.Transaction.DistributionCenterID = parties.DistributionCenterID
+	s.Transaction.StoreID = parties.StoreID
+	s.Transaction.PurchaserAccountID = parties.PurchaserAccountID
+	s.Transaction.VendorID = parties.VendorID
+	s.Transaction.DeliverToCompanyName = parties.ShipTo.CompanyName
+	s.Transaction.DeliverToContactName = parties.ShipTo.ContactName
+	s.Transaction.DeliverToAddress1 = parties.ShipTo.Address1
+	s.Transaction.DeliverToAddress2 = parties.ShipTo.Address2
+	s.Transaction.DeliverToCityName = parties.ShipTo.CityName
+	s.Transaction.DeliverToStateCode = parties.ShipTo.StateCode
+	s.Transaction.DeliverToPostalCode = parties.ShipTo.PostalCode
+	s.Transaction.DeliverToCountryCode = parties.ShipTo.CountryCode
+
+	return unmapped, nil
+}
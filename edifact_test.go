@@ -0,0 +1,275 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandard850V4EDIFACT(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/850.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard850V4 Standard850V4
+	err := standard850V4.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+	standard850V4.Transaction.ReleaseNumber = "2"
+	standard850V4.Transaction.POTime = "101500"
+	standard850V4.Transaction.CarrierRoutingDetails = "UPS GROUND"
+	standard850V4.Transaction.SpecialDeliveryInstructions = "Leave at the front desk"
+	standard850V4.Transaction.SpecialOrderInstructions = "Ship complete"
+
+	byteArrayPointer, err := standard850V4.ToEDIFACT(ctx, EDIFACTOptions{
+		LineBreak:                   "\n",
+		InterchangeControlReference: "905",
+	})
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+	err = ioutil.WriteFile("./examples/850edifact-0.txt", *byteArrayPointer, 0644)
+	assert.Nil(t, err)
+
+	segments := strings.Split(strings.TrimSuffix(string(*byteArrayPointer), "'\n"), "'\n")
+	if assert.True(t, len(segments) > 4) {
+		assert.Equal(t, "UNA:+.? ", segments[0])
+		assert.Equal(t, "UNH+1+ORDERS:D:96A:UN", segments[2])
+		assert.Equal(t, "BGM+220:::SA+12345678+9", segments[3])
+		assert.Equal(t, "DTM+137:"+standard850V4.Transaction.PODate+"101500:204", segments[4])
+		assert.Contains(t, segments, "RFF+ON:12345678:2")
+		assert.Contains(t, segments, "TDT+20++++:::UPS GROUND")
+		assert.Contains(t, segments, "FTX+DEL+++Leave at the front desk")
+		assert.Contains(t, segments, "LIN+2++00821780002799:EN")
+		assert.Contains(t, segments, "PRI+AAA:1.85")
+		assert.Equal(t, "UNZ+1+905", segments[len(segments)-1])
+	}
+
+	var roundTrip Standard850V4
+	unmapped, err := roundTrip.FromEDIFACT(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+	assert.Empty(t, unmapped)
+
+	assert.Equal(t, "905", roundTrip.EnvelopeHeaderV3.InterchangeID)
+	assert.Equal(t, standard850V4.Transaction.PurchaseOrderNumber, roundTrip.Transaction.PurchaseOrderNumber)
+	assert.Equal(t, standard850V4.Transaction.PODate, roundTrip.Transaction.PODate)
+	assert.Equal(t, standard850V4.Transaction.POTime, roundTrip.Transaction.POTime)
+	assert.Equal(t, standard850V4.Transaction.PurchaseOrderTypeCode, roundTrip.Transaction.PurchaseOrderTypeCode)
+	assert.Equal(t, standard850V4.Transaction.ReleaseNumber, roundTrip.Transaction.ReleaseNumber)
+	assert.Equal(t, standard850V4.Transaction.CarrierRoutingDetails, roundTrip.Transaction.CarrierRoutingDetails)
+	assert.Equal(t, standard850V4.Transaction.SpecialDeliveryInstructions, roundTrip.Transaction.SpecialDeliveryInstructions)
+	assert.Equal(t, standard850V4.Transaction.SpecialOrderInstructions, roundTrip.Transaction.SpecialOrderInstructions)
+	assert.Equal(t, standard850V4.Transaction.DistributionCenterID, roundTrip.Transaction.DistributionCenterID)
+	assert.Equal(t, standard850V4.Transaction.DeliverToAddress1, roundTrip.Transaction.DeliverToAddress1)
+	assert.Equal(t, standard850V4.Transaction.DeliverToPostalCode, roundTrip.Transaction.DeliverToPostalCode)
+	assert.Equal(t, standard850V4.Trailer.TotalQuantityOrdered, roundTrip.Trailer.TotalQuantityOrdered)
+	if assert.Len(t, roundTrip.OtherCharges, len(standard850V4.OtherCharges)) {
+		for otherChargeKey, otherCharge := range standard850V4.OtherCharges {
+			assert.Equal(t, otherCharge.OtherChargeDescription, roundTrip.OtherCharges[otherChargeKey].OtherChargeDescription)
+			assert.Equal(t, otherCharge.OtherChargeAmount, roundTrip.OtherCharges[otherChargeKey].OtherChargeAmount)
+		}
+	}
+	if assert.Len(t, roundTrip.LineItems, len(standard850V4.LineItems)) {
+		for lineItemKey, lineItem := range standard850V4.LineItems {
+			assert.Equal(t, lineItem.ItemIdentificationGTIN, roundTrip.LineItems[lineItemKey].ItemIdentificationGTIN)
+			assert.Equal(t, lineItem.MasterStyle, roundTrip.LineItems[lineItemKey].MasterStyle)
+			assert.Equal(t, lineItem.ColorCode, roundTrip.LineItems[lineItemKey].ColorCode)
+			assert.Equal(t, lineItem.SizeCode, roundTrip.LineItems[lineItemKey].SizeCode)
+			assert.Equal(t, lineItem.QuantityOrdered, roundTrip.LineItems[lineItemKey].QuantityOrdered)
+			assert.Equal(t, lineItem.PurchaseUnitPrice, roundTrip.LineItems[lineItemKey].PurchaseUnitPrice)
+		}
+	}
+
+}
+
+func TestStandard850V4EDIFACTRelease(t *testing.T) {
+
+	ctx := context.Background()
+
+	standard850V4 := Standard850V4s[0]
+	standard850V4.Transaction.DeliverToCompanyName = "O'Brien + Sons"
+	standard850V4.Transaction.DeliverToAddress2 = "Suite 2:3?"
+
+	byteArrayPointer, err := standard850V4.ToEDIFACT(ctx, EDIFACTOptions{})
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+	assert.Contains(t, string(*byteArrayPointer), "O?'Brien ?+ Sons")
+	assert.Contains(t, string(*byteArrayPointer), "Suite 2?:3??")
+
+	var roundTrip Standard850V4
+	_, err = roundTrip.FromEDIFACT(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+	assert.Equal(t, "O'Brien + Sons", roundTrip.Transaction.DeliverToCompanyName)
+	assert.Equal(t, "Suite 2:3?", roundTrip.Transaction.DeliverToAddress2)
+
+	// Service String Advice
+	byteArrayPointer, err = standard850V4.ToEDIFACT(ctx, EDIFACTOptions{
+		ComponentSeparator: '>',
+		ElementSeparator:   '*',
+		DecimalMark:        ',',
+		ReleaseCharacter:   '!',
+		SegmentTerminator:  '~',
+	})
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+	assert.True(t, strings.HasPrefix(string(*byteArrayPointer), "UNA>*,! ~UNB*UNOC>3*"))
+	assert.Contains(t, string(*byteArrayPointer), "PRI*AAA>1,85~")
+	assert.Contains(t, string(*byteArrayPointer), "LIN*2**00821780002799>EN~")
+
+	roundTrip = Standard850V4{}
+	_, err = roundTrip.FromEDIFACT(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+	assert.Equal(t, "O'Brien + Sons", roundTrip.Transaction.DeliverToCompanyName)
+	if assert.Len(t, roundTrip.LineItems, 2) {
+		assert.Equal(t, 185, roundTrip.LineItems[1].PurchaseUnitPrice)
+	}
+
+}
+
+func TestStandard850V4FromEDIFACTUnmapped(t *testing.T) {
+
+	ctx := context.Background()
+
+	req := "UNB+UNOC:3+SENDER+RECIPIENT+210311:1200+7'" +
+		"UNH+1+ORDERS:D:96A:UN'" +
+		"BGM+220+PO1+9'" +
+		"FTX+AAI+++Leave at dock'" +
+		"LIN+1++00821780002799:EN'" +
+		"QTY+21:6:EA'" +
+		"ALC+A++++::::Promo'" +
+		"UNT+7+1'" +
+		"UNZ+1+7'"
+
+	var standard850V4 Standard850V4
+	unmapped, err := standard850V4.FromEDIFACT(ctx, []byte(req))
+	assert.Nil(t, err)
+	assert.Equal(t, "PO1", standard850V4.Transaction.PurchaseOrderNumber)
+	if assert.Len(t, unmapped, 2) {
+		assert.Equal(t, "FTX", unmapped[0].Tag)
+		assert.Equal(t, "Leave at dock", unmapped[0].Component(4, 1))
+		assert.Equal(t, "ALC", unmapped[1].Tag)
+		assert.Equal(t, 7, unmapped[1].Position)
+	}
+
+	standard850V4 = Standard850V4{}
+	unmapped, err = standard850V4.FromEDIFACT(ctx, []byte(strings.Replace(req, "00821780002799:EN", "2002:VN", 1)))
+	assert.Nil(t, err)
+	if assert.Len(t, standard850V4.LineItems, 1) {
+		assert.Equal(t, "", standard850V4.LineItems[0].ItemIdentificationGTIN)
+	}
+	if assert.Len(t, unmapped, 3) {
+		assert.Equal(t, "LIN", unmapped[1].Tag)
+	}
+
+	_, err = standard850V4.FromEDIFACT(ctx, []byte(strings.Replace(req, "ORDERS", "DESADV", 1)))
+	assert.NotNil(t, err)
+
+}
+
+func TestStandard856V7EDIFACT(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/856.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard856V7 Standard856V7
+	err := standard856V7.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	byteArrayPointer, err := standard856V7.ToEDIFACT(ctx, EDIFACTOptions{
+		LineBreak: "\n",
+	})
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+	err = ioutil.WriteFile("./examples/856edifact-0.txt", *byteArrayPointer, 0644)
+	assert.Nil(t, err)
+
+	segments := strings.Split(strings.TrimSuffix(string(*byteArrayPointer), "'\n"), "'\n")
+	assert.Contains(t, segments, "UNH+1+DESADV:D:96A:UN")
+	assert.Contains(t, segments, "CPS+1")
+	assert.Contains(t, segments, "CPS+2+1")
+	assert.Contains(t, segments, "CPS+3+2")
+	assert.Contains(t, segments, "LIN+1++00707738003265:EN")
+	assert.Contains(t, segments, "GIN+BJ+107077380000002807")
+	assert.Contains(t, segments, "MEA+PD+AAB+LBR:3.8")
+	assert.Contains(t, segments, "DTM+171:20060601122325:204")
+	assert.Contains(t, segments, "CNT+2:4")
+
+	var roundTrip Standard856V7
+	unmapped, err := roundTrip.FromEDIFACT(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+	assert.Empty(t, unmapped)
+
+	if assert.Len(t, roundTrip.Pallets, len(standard856V7.Pallets)) {
+		for palletKey, pallet := range standard856V7.Pallets {
+			assert.Equal(t, pallet.PalletID, roundTrip.Pallets[palletKey].PalletID)
+			if !assert.Len(t, roundTrip.Pallets[palletKey].Shipments, len(pallet.Shipments)) {
+				continue
+			}
+			for shipmentKey, shipment := range pallet.Shipments {
+				roundTripShipment := roundTrip.Pallets[palletKey].Shipments[shipmentKey]
+				assert.Equal(t, shipment.ManufacturersSerialCaseNumber, roundTripShipment.ManufacturersSerialCaseNumber)
+				assert.Equal(t, shipment.CarrierTrackingNumber, roundTripShipment.CarrierTrackingNumber)
+				assert.Equal(t, shipment.BuyersPurchaseOrderNumber, roundTripShipment.BuyersPurchaseOrderNumber)
+				assert.Equal(t, shipment.PODate, roundTripShipment.PODate)
+				assert.Equal(t, shipment.POTime, roundTripShipment.POTime)
+				assert.Equal(t, shipment.ManufacturersOrderNumber, roundTripShipment.ManufacturersOrderNumber)
+				assert.Equal(t, shipment.CaseWeight, roundTripShipment.CaseWeight)
+				assert.Equal(t, shipment.FreightCharge, roundTripShipment.FreightCharge)
+				if !assert.Len(t, roundTripShipment.LineItems, len(shipment.LineItems)) {
+					continue
+				}
+				for lineItemKey, lineItem := range shipment.LineItems {
+					roundTripLineItem := roundTripShipment.LineItems[lineItemKey]
+					assert.Equal(t, lineItem.LineItemNumber, roundTripLineItem.LineItemNumber)
+					assert.Equal(t, lineItem.ItemIdentificationGTIN, roundTripLineItem.ItemIdentificationGTIN)
+					assert.Equal(t, lineItem.MasterStyle, roundTripLineItem.MasterStyle)
+					assert.Equal(t, lineItem.DetailStyle, roundTripLineItem.DetailStyle)
+					assert.Equal(t, lineItem.ColorCode, roundTripLineItem.ColorCode)
+					assert.Equal(t, lineItem.SizeCode, roundTripLineItem.SizeCode)
+					assert.Equal(t, lineItem.QuantityShipped, roundTripLineItem.QuantityShipped)
+					assert.Equal(t, lineItem.UnitOrBasisForMeasurementCode, roundTripLineItem.UnitOrBasisForMeasurementCode)
+					assert.Equal(t, lineItem.CountryOfOrigin, roundTripLineItem.CountryOfOrigin)
+					assert.Equal(t, lineItem.ManufacturersLotID, roundTripLineItem.ManufacturersLotID)
+					assert.Equal(t, lineItem.BuyersPurchaseOrderNumber, roundTripLineItem.BuyersPurchaseOrderNumber)
+				}
+			}
+		}
+	}
+
+	// Header
+	standard856V7 = Standard856V7s[0]
+	byteArrayPointer, err = standard856V7.ToEDIFACT(ctx, EDIFACTOptions{})
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+
+	roundTrip = Standard856V7{}
+	unmapped, err = roundTrip.FromEDIFACT(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+	assert.Empty(t, unmapped)
+	assert.Equal(t, standard856V7.Transaction.ShipmentNumber, roundTrip.Transaction.ShipmentNumber)
+	assert.Equal(t, standard856V7.Transaction.ASNDate, roundTrip.Transaction.ASNDate)
+	assert.Equal(t, standard856V7.Transaction.BOLNumber, roundTrip.Transaction.BOLNumber)
+	assert.Equal(t, standard856V7.Transaction.CarrierRoutingDetails, roundTrip.Transaction.CarrierRoutingDetails)
+	assert.Equal(t, standard856V7.Transaction.DistributionCenterID, roundTrip.Transaction.DistributionCenterID)
+	assert.Equal(t, standard856V7.Transaction.DeliverToCityName, roundTrip.Transaction.DeliverToCityName)
+
+}
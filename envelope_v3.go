@@ -31,8 +31,12 @@ func (s *EnvelopeHeaderV3) Prep(ctx context.Context) (error){
 
 	s.Header = "EASI"
 	s.VersionNumber = "3.0"
-	s.SenderQualifier = "01"
-	s.ReceiverQualifier = "01"
+	if s.SenderQualifier == "" {
+		s.SenderQualifier = "01"
+	}
+	if s.ReceiverQualifier == "" {
+		s.ReceiverQualifier = "01"
+	}
 	s.FileCreationDate = time.Now().Format("20060102")
 	s.FileCreationTime = time.Now().Format("150405")
 	s.TimeZone = "UTC"
@@ -45,8 +49,15 @@ func (s *EnvelopeHeaderV3) Prep(ctx context.Context) (error){
 
 func (s *EnvelopeTrailerV3) Prep(ctx context.Context) (error){
 
+	return s.PrepDocuments(ctx, 1)
+}
+
+// PrepDocuments prepares the trailer of an envelope carrying numberOfDocuments
+// documents.
+func (s *EnvelopeTrailerV3) PrepDocuments(ctx context.Context, numberOfDocuments int) error {
+
 	s.RoutingTrailerRecord = "EASX"
-	s.NumberOfDocuments = 1
+	s.NumberOfDocuments = numberOfDocuments
 
 	return nil
 }
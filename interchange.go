@@ -0,0 +1,354 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/jszwec/csvutil"
+)
+
+// InterchangeV2 carries any number of documents of one transaction type in a
+// single EASI 2.0 envelope. Documents are read back with NewDocument, as many
+// as the envelope trailer counts.
+type InterchangeV2 struct {
+	EnvelopeHeaderV2  EnvelopeHeaderV2
+	Documents         []Document
+	EnvelopeTrailerV2 EnvelopeTrailerV2
+	NewDocument       func() Document
+}
+
+// InterchangeV3 is InterchangeV2 in an EASI 3.0 envelope.
+type InterchangeV3 struct {
+	EnvelopeHeaderV3  EnvelopeHeaderV3
+	Documents         []Document
+	EnvelopeTrailerV3 EnvelopeTrailerV3
+	NewDocument       func() Document
+}
+
+func (s *InterchangeV2) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV2.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV2.PrepDocuments(ctx, len(s.Documents))
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *InterchangeV2) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Documents
+	records, transactionType, errRecords := interchangeRecords(ctx, s.Documents, s.EnvelopeHeaderV2.TransactionType)
+	if errRecords != nil {
+		return nil, errRecords
+	}
+	s.EnvelopeHeaderV2.TransactionType = transactionType
+
+	return interchangeBytes(ctx, s.EnvelopeHeaderV2, records, s.EnvelopeTrailerV2)
+}
+
+func (s *InterchangeV2) FromBytes(ctx context.Context, req []byte) error {
+
+	if s.NewDocument == nil {
+		return fmt.Errorf("interchange has no NewDocument to read documents into")
+	}
+
+	header, sections, trailer, errSplit := interchangeSplit(ctx, req)
+	if errSplit != nil {
+		return errSplit
+	}
+
+	// Envelope
+	errHeader := s.EnvelopeHeaderV2.FromSlice(ctx, header)
+	if errHeader != nil {
+		return errHeader
+	}
+	errTrailer := s.EnvelopeTrailerV2.FromSlice(ctx, trailer)
+	if errTrailer != nil {
+		return errTrailer
+	}
+	groups, errDocuments := interchangeDocuments(sections, s.EnvelopeTrailerV2.NumberOfDocuments)
+	if errDocuments != nil {
+		return errDocuments
+	}
+
+	// Documents
+	s.Documents = nil
+	for _, group := range groups {
+		envelopeTrailerV2 := s.EnvelopeTrailerV2
+		envelopeTrailerV2.NumberOfDocuments = 1
+		byteArrayPointer, errBytes := interchangeBytes(ctx, s.EnvelopeHeaderV2, group, envelopeTrailerV2)
+		if errBytes != nil {
+			return errBytes
+		}
+		document := s.NewDocument()
+		errDocument := document.FromBytes(ctx, *byteArrayPointer)
+		if errDocument != nil {
+			return errDocument
+		}
+		s.Documents = append(s.Documents, document)
+	}
+
+	return nil
+}
+
+func (s *InterchangeV3) Prep(ctx context.Context) error {
+
+	// Header
+	errHeader := s.EnvelopeHeaderV3.Prep(ctx)
+	if errHeader != nil {
+		return errHeader
+	}
+
+	// Trailer
+	errTrailer := s.EnvelopeTrailerV3.PrepDocuments(ctx, len(s.Documents))
+	if errTrailer != nil {
+		return errTrailer
+	}
+
+	return nil
+}
+
+func (s *InterchangeV3) ToBytes(ctx context.Context) (*[]byte, error) {
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+
+	// Documents
+	records, transactionType, errRecords := interchangeRecords(ctx, s.Documents, s.EnvelopeHeaderV3.TransactionType)
+	if errRecords != nil {
+		return nil, errRecords
+	}
+	s.EnvelopeHeaderV3.TransactionType = transactionType
+
+	return interchangeBytes(ctx, s.EnvelopeHeaderV3, records, s.EnvelopeTrailerV3)
+}
+
+func (s *InterchangeV3) FromBytes(ctx context.Context, req []byte) error {
+
+	if s.NewDocument == nil {
+		return fmt.Errorf("interchange has no NewDocument to read documents into")
+	}
+
+	header, sections, trailer, errSplit := interchangeSplit(ctx, req)
+	if errSplit != nil {
+		return errSplit
+	}
+
+	// Envelope
+	errHeader := s.EnvelopeHeaderV3.FromSlice(ctx, header)
+	if errHeader != nil {
+		return errHeader
+	}
+	errTrailer := s.EnvelopeTrailerV3.FromSlice(ctx, trailer)
+	if errTrailer != nil {
+		return errTrailer
+	}
+	groups, errDocuments := interchangeDocuments(sections, s.EnvelopeTrailerV3.NumberOfDocuments)
+	if errDocuments != nil {
+		return errDocuments
+	}
+
+	// Documents
+	s.Documents = nil
+	for _, group := range groups {
+		envelopeTrailerV3 := s.EnvelopeTrailerV3
+		envelopeTrailerV3.NumberOfDocuments = 1
+		byteArrayPointer, errBytes := interchangeBytes(ctx, s.EnvelopeHeaderV3, group, envelopeTrailerV3)
+		if errBytes != nil {
+			return errBytes
+		}
+		document := s.NewDocument()
+		errDocument := document.FromBytes(ctx, *byteArrayPointer)
+		if errDocument != nil {
+			return errDocument
+		}
+		s.Documents = append(s.Documents, document)
+	}
+
+	return nil
+}
+
+// interchangeRecords writes each document and keeps the records between its
+// envelope header and trailer. Every document must carry the same transaction
+// type as the first one, or as the interchange when it is already set, and a
+// document of several sections must be the only one.
+func interchangeRecords(ctx context.Context, documents []Document, transactionType string) ([][]string, string, error) {
+
+	var records [][]string
+	for documentKey, document := range documents {
+		byteArrayPointer, err := document.ToBytes(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+
+		documentRecords, err := interchangeRead(*byteArrayPointer)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(documents) > 1 {
+			var sections int
+			for _, record := range documentRecords {
+				if record[0] == "01" {
+					sections++
+				}
+			}
+			if sections > 1 {
+				return nil, "", fmt.Errorf("document %d holds %d sections, which cannot be told apart from other documents in an interchange", documentKey+1, sections)
+			}
+		}
+		for _, record := range documentRecords {
+			switch record[0] {
+			case "EASI":
+				var documentTransactionType string
+				if len(record) > 10 {
+					documentTransactionType = record[10]
+				}
+				if transactionType == "" {
+					transactionType = documentTransactionType
+				}
+				if documentTransactionType != transactionType {
+					return nil, "", fmt.Errorf("document %d is a %s in a %s interchange", documentKey+1, documentTransactionType, transactionType)
+				}
+			case "EASX":
+			default:
+				records = append(records, record)
+			}
+		}
+	}
+
+	return records, transactionType, nil
+}
+
+// interchangeSplit returns the envelope header and trailer records, and the
+// records of each section. A section runs from its 01 record to its 09
+// trailer record, or to the next 01 record for documents without a trailer
+// such as the 997.
+func interchangeSplit(ctx context.Context, req []byte) ([]string, [][][]string, []string, error) {
+
+	records, err := interchangeRead(req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var header, trailer []string
+	var sections [][][]string
+	closed := true
+	for _, record := range records {
+		switch {
+		case record[0] == "EASI":
+			header = record
+		case record[0] == "EASX":
+			trailer = record
+		case record[0] == "01" || closed:
+			sections = append(sections, [][]string{record})
+			closed = record[0] == "09"
+		default:
+			sections[len(sections)-1] = append(sections[len(sections)-1], record)
+			closed = record[0] == "09"
+		}
+	}
+
+	return header, sections, trailer, nil
+}
+
+// interchangeDocuments groups the sections into the documents the envelope
+// trailer counts. Documents such as a multi-section 846 carry several
+// sections, and the file does not say where one ends, so the sections only
+// split when there is one per document or all belong to a single document.
+func interchangeDocuments(sections [][][]string, numberOfDocuments int) ([][][]string, error) {
+
+	switch {
+	case len(sections) == numberOfDocuments:
+		return sections, nil
+	case numberOfDocuments == 1:
+		var records [][]string
+		for _, section := range sections {
+			records = append(records, section...)
+		}
+		return [][][]string{records}, nil
+	}
+
+	return nil, fmt.Errorf("interchange of %d documents holds %d sections", numberOfDocuments, len(sections))
+}
+
+func interchangeRead(req []byte) ([][]string, error) {
+
+	r := csv.NewReader(bytes.NewReader(req))
+	r.Comma = '\t'
+	r.FieldsPerRecord = -1
+
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 || (len(record) == 1 && record[0] == "") {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func interchangeBytes(ctx context.Context, envelopeHeader interface{}, records [][]string, envelopeTrailer interface{}) (*[]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	enc := csvutil.NewEncoder(w)
+	enc.AutoHeader = false
+
+	// Envelope Header
+	errEnvelopeHeader := enc.Encode(envelopeHeader)
+	if errEnvelopeHeader != nil {
+		return nil, errEnvelopeHeader
+	}
+
+	// Documents
+	for _, record := range records {
+		errRecord := w.Write(record)
+		if errRecord != nil {
+			return nil, errRecord
+		}
+	}
+
+	// Envelope Trailer
+	errEnvelopeTrailer := enc.Encode(envelopeTrailer)
+	if errEnvelopeTrailer != nil {
+		return nil, errEnvelopeTrailer
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	byteArray := buf.Bytes()
+
+	return &byteArray, nil
+}
@@ -0,0 +1,219 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterchangeV3ToBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	interchangeV3 := InterchangeV3{
+		EnvelopeHeaderV3: EnvelopeHeaderV3{
+			InterchangeID: "202102268484920",
+			ReceiverID:    "123456789",
+			SenderID:      "383601069",
+		},
+		EnvelopeTrailerV3: EnvelopeTrailerV3{
+			InterchangeID: "202102268484920",
+		},
+	}
+	for _, interchangeID := range []string{"123456789", "123456790", "123456791"} {
+		interchangeV3.Documents = append(interchangeV3.Documents, &Standard997V2{
+			Body: Standard997V2Body{
+				SenderID:                           "014628093",
+				ProductionOrTest:                   "T",
+				InterchangeID:                      interchangeID,
+				TransactionSetAcknowledgementCodes: "A",
+			},
+		})
+	}
+
+	byteArrayPointer, err := interchangeV3.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer != nil {
+		err := ioutil.WriteFile("./examples/interchange-0.txt", *byteArrayPointer, 0644)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, "997", interchangeV3.EnvelopeHeaderV3.TransactionType)
+	assert.Equal(t, 3, interchangeV3.EnvelopeTrailerV3.NumberOfDocuments)
+
+	interchangeV3.Documents = append(interchangeV3.Documents, &Standard214V1{})
+	_, err = interchangeV3.ToBytes(ctx)
+	assert.NotNil(t, err)
+
+}
+
+func TestInterchangeV3FromBytes(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/interchange-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var interchangeV3 InterchangeV3
+	err := interchangeV3.FromBytes(ctx, bytes)
+	assert.NotNil(t, err)
+
+	interchangeV3.NewDocument = func() Document {
+		return &Standard997V2{}
+	}
+	err = interchangeV3.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, interchangeV3.EnvelopeTrailerV3.NumberOfDocuments)
+	if assert.Len(t, interchangeV3.Documents, 3) {
+		standard997V2 := interchangeV3.Documents[2].(*Standard997V2)
+		assert.Equal(t, "123456791", standard997V2.Body.InterchangeID)
+		assert.Equal(t, "202102268484920", standard997V2.EnvelopeHeaderV3.InterchangeID)
+		assert.Equal(t, 1, standard997V2.EnvelopeTrailerV3.NumberOfDocuments)
+	}
+
+}
+
+func TestInterchangeV2RoundTrip(t *testing.T) {
+
+	ctx := context.Background()
+
+	interchangeV2 := InterchangeV2{
+		EnvelopeHeaderV2: EnvelopeHeaderV2{
+			InterchangeID: "202102268484921",
+			ReceiverID:    "123456789",
+			SenderID:      "383601069",
+		},
+		EnvelopeTrailerV2: EnvelopeTrailerV2{
+			InterchangeID: "202102268484921",
+		},
+		NewDocument: func() Document {
+			return &Standard850V1{}
+		},
+	}
+	for _, purchaseOrderNumber := range []string{"12345678", "12345679"} {
+		standard850V1 := standard850V1s[0]
+		standard850V1.Transaction.PurchaseOrderNumber = purchaseOrderNumber
+		interchangeV2.Documents = append(interchangeV2.Documents, &standard850V1)
+	}
+
+	byteArrayPointer, err := interchangeV2.ToBytes(ctx)
+	assert.Nil(t, err)
+
+	var split InterchangeV2
+	split.NewDocument = interchangeV2.NewDocument
+	err = split.FromBytes(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, split.EnvelopeTrailerV2.NumberOfDocuments)
+	if assert.Len(t, split.Documents, 2) {
+		assert.Equal(t, "12345679", split.Documents[1].(*Standard850V1).Transaction.PurchaseOrderNumber)
+		assert.Len(t, split.Documents[1].(*Standard850V1).LineItems, 2)
+	}
+
+}
+
+func TestInterchangeV3Sections(t *testing.T) {
+
+	ctx := context.Background()
+
+	standard846V3 := Standard846V3s[0]
+	interchangeV3 := InterchangeV3{
+		EnvelopeHeaderV3:  standard846V3.EnvelopeHeaderV3,
+		EnvelopeTrailerV3: standard846V3.EnvelopeTrailerV3,
+		Documents:         []Document{&standard846V3},
+		NewDocument: func() Document {
+			return &Standard846V3{}
+		},
+	}
+
+	byteArrayPointer, err := interchangeV3.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+
+	var split InterchangeV3
+	split.NewDocument = interchangeV3.NewDocument
+	err = split.FromBytes(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, split.EnvelopeTrailerV3.NumberOfDocuments)
+	if assert.Len(t, split.Documents, 1) {
+		sections := split.Documents[0].(*Standard846V3).Sections
+		if assert.Len(t, sections, 2) {
+			assert.Equal(t, "CLT1", sections[0].Header.DistributionCenterID)
+			assert.Equal(t, "RNO1", sections[1].Header.DistributionCenterID)
+			assert.Len(t, sections[0].LineItems, 2)
+		}
+	}
+
+	// Document Count
+	miscounted := strings.Replace(string(*byteArrayPointer), "EASX\t202102268484912\t1", "EASX\t202102268484912\t3", 1)
+	err = split.FromBytes(ctx, []byte(miscounted))
+	assert.NotNil(t, err)
+
+	standard846V3Copy := Standard846V3s[0]
+	interchangeV3.Documents = append(interchangeV3.Documents, &standard846V3Copy)
+	_, err = interchangeV3.ToBytes(ctx)
+	assert.NotNil(t, err)
+
+}
+
+func TestInterchangeV2Standard856V5(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/856_173384223_20210311005605.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard856V5 Standard856V5
+	err := standard856V5.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+
+	// Transactions
+	interchangeV2 := InterchangeV2{
+		EnvelopeHeaderV2:  standard856V5.EnvelopeHeaderV2,
+		EnvelopeTrailerV2: standard856V5.EnvelopeTrailerV2,
+		Documents:         []Document{&standard856V5},
+		NewDocument: func() Document {
+			return &Standard856V5{}
+		},
+	}
+	byteArrayPointer, err := interchangeV2.ToBytes(ctx)
+	assert.Nil(t, err)
+	if byteArrayPointer == nil {
+		return
+	}
+
+	var split InterchangeV2
+	split.NewDocument = interchangeV2.NewDocument
+	err = split.FromBytes(ctx, *byteArrayPointer)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, split.EnvelopeTrailerV2.NumberOfDocuments)
+	if assert.Len(t, split.Documents, 1) {
+		transactions := split.Documents[0].(*Standard856V5).Transactions
+		if assert.Len(t, transactions, len(standard856V5.Transactions)) {
+			assert.Equal(t, standard856V5.Transactions[29].Header, transactions[29].Header)
+			assert.Len(t, transactions[29].Pallets, len(standard856V5.Transactions[29].Pallets))
+		}
+	}
+
+	// Documents
+	split = InterchangeV2{NewDocument: interchangeV2.NewDocument}
+	err = split.FromBytes(ctx, bytes)
+	assert.Nil(t, err)
+	if assert.Len(t, split.Documents, 30) {
+		assert.Len(t, split.Documents[0].(*Standard856V5).Transactions, 1)
+		assert.Equal(t, standard856V5.Transactions[29].Pallets[0].LineItems, split.Documents[29].(*Standard856V5).Transactions[0].Pallets[0].LineItems)
+	}
+
+}
@@ -0,0 +1,233 @@
+package easi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Ledger records outbound interchanges and the 997s that acknowledge them.
+// An entry is overdue once Deadline has passed since it was sent without a
+// 997. Now defaults to time.Now.
+type Ledger struct {
+	Store    LedgerStore
+	Deadline time.Duration
+	Now      func() time.Time
+}
+
+// LedgerEntry is one outbound interchange. Status is empty until a 997
+// arrives and holds its acknowledgement code after.
+type LedgerEntry struct {
+	InterchangeID   string
+	TransactionType string
+	SenderID        string
+	ReceiverID      string
+	SentAt          time.Time
+	Status          string
+	AcknowledgedAt  time.Time
+}
+
+// LedgerStore keeps ledger entries by InterchangeID. Get reports false for an
+// unknown interchange.
+type LedgerStore interface {
+	Get(ctx context.Context, interchangeID string) (LedgerEntry, bool, error)
+	Put(ctx context.Context, entry LedgerEntry) error
+	List(ctx context.Context) ([]LedgerEntry, error)
+}
+
+// FileLedgerStore keeps every entry in one JSON file at Path, rewritten on
+// each Put.
+type FileLedgerStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (s *LedgerEntry) Acknowledged() bool {
+
+	return s.Status != ""
+}
+
+// Sent records an outbound document under the InterchangeID of its envelope.
+func (s *Ledger) Sent(ctx context.Context, document Document) (LedgerEntry, error) {
+
+	envelopeHeader, _, err := documentEnvelopeHeader(document)
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+	if envelopeHeader.InterchangeID == "" {
+		return LedgerEntry{}, fmt.Errorf("%T has no InterchangeID to record", document)
+	}
+
+	entry := LedgerEntry{
+		InterchangeID:   envelopeHeader.InterchangeID,
+		TransactionType: envelopeHeader.TransactionType,
+		SenderID:        envelopeHeader.SenderID,
+		ReceiverID:      envelopeHeader.ReceiverID,
+		SentAt:          s.now(),
+	}
+
+	return entry, s.Store.Put(ctx, entry)
+}
+
+// Received matches a Standard997V1, Standard997V2 or Standard997V3 to the
+// interchange it acknowledges and stores its acknowledgement code.
+func (s *Ledger) Received(ctx context.Context, acknowledgement Document) (LedgerEntry, error) {
+
+	var interchangeID, code string
+	switch x := acknowledgement.(type) {
+	case *Standard997V1:
+		interchangeID, code = x.Body.InterchangeID, x.Body.TransactionSetAcknowledgementCodes
+	case *Standard997V2:
+		interchangeID, code = x.Body.InterchangeID, x.Body.TransactionSetAcknowledgementCodes
+	case *Standard997V3:
+		interchangeID, code = x.Body.InterchangeID, x.Body.TransactionSetAcknowledgementCodes
+	default:
+		return LedgerEntry{}, fmt.Errorf("%T is not a 997", acknowledgement)
+	}
+
+	entry, ok, err := s.Store.Get(ctx, interchangeID)
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+	if !ok {
+		return LedgerEntry{}, fmt.Errorf("997 for unknown interchange %s", interchangeID)
+	}
+	entry.Status = code
+	entry.AcknowledgedAt = s.now()
+
+	return entry, s.Store.Put(ctx, entry)
+}
+
+// Overdue lists the interchanges without a 997 sent more than Deadline ago,
+// oldest first.
+func (s *Ledger) Overdue(ctx context.Context) ([]LedgerEntry, error) {
+
+	entries, err := s.Store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var overdue []LedgerEntry
+	cutoff := s.now().Add(-s.Deadline)
+	for _, entry := range entries {
+		if !entry.Acknowledged() && entry.SentAt.Before(cutoff) {
+			overdue = append(overdue, entry)
+		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].SentAt.Before(overdue[j].SentAt)
+	})
+
+	return overdue, nil
+}
+
+func (s *Ledger) now() time.Time {
+
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+func (s *FileLedgerStore) Get(ctx context.Context, interchangeID string) (LedgerEntry, bool, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return LedgerEntry{}, false, err
+	}
+	entry, ok := entries[interchangeID]
+
+	return entry, ok, nil
+}
+
+func (s *FileLedgerStore) Put(ctx context.Context, entry LedgerEntry) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	entries[entry.InterchangeID] = entry
+
+	return s.write(entries)
+}
+
+func (s *FileLedgerStore) List(ctx context.Context) ([]LedgerEntry, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []LedgerEntry
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].InterchangeID < list[j].InterchangeID
+	})
+
+	return list, nil
+}
+
+// read returns no entries while the file does not exist yet.
+func (s *FileLedgerStore) read() (map[string]LedgerEntry, error) {
+
+	entries := map[string]LedgerEntry{}
+	bytes, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes) == 0 {
+		return entries, nil
+	}
+	err = json.Unmarshal(bytes, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// write replaces the file through a rename so a failed write leaves the
+// previous ledger in place.
+func (s *FileLedgerStore) write(entries map[string]LedgerEntry) error {
+
+	bytes, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(bytes)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), s.Path)
+}
@@ -0,0 +1,72 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedger(t *testing.T) {
+
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "ledger")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2021, 2, 26, 12, 0, 0, 0, time.UTC)
+	ledger := Ledger{
+		Store:    &FileLedgerStore{Path: filepath.Join(dir, "ledger.json")},
+		Deadline: 24 * time.Hour,
+		Now: func() time.Time {
+			return now
+		},
+	}
+
+	// Sent
+	standard850V4 := Standard850V4s[0]
+	_, err = ledger.Sent(ctx, &standard850V4)
+	assert.Nil(t, err)
+	standard850V1 := standard850V1s[0]
+	standard850V1.EnvelopeHeaderV2.InterchangeID = "202102268484913"
+	_, err = ledger.Sent(ctx, &standard850V1)
+	assert.Nil(t, err)
+
+	// Received
+	now = now.Add(2 * time.Hour)
+	acknowledgement, err := Acknowledge(ctx, &standard850V4, ValidationReport{})
+	assert.Nil(t, err)
+	entry, err := ledger.Received(ctx, acknowledgement)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", entry.Status)
+	assert.True(t, entry.Acknowledged())
+
+	_, err = ledger.Received(ctx, &Standard997V2{Body: Standard997V2Body{InterchangeID: "unknown"}})
+	assert.NotNil(t, err)
+	_, err = ledger.Received(ctx, &standard850V4)
+	assert.NotNil(t, err)
+
+	// Overdue
+	overdue, err := ledger.Overdue(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, overdue)
+
+	now = now.Add(24 * time.Hour)
+	reopened := Ledger{
+		Store:    &FileLedgerStore{Path: filepath.Join(dir, "ledger.json")},
+		Deadline: ledger.Deadline,
+		Now:      ledger.Now,
+	}
+	overdue, err = reopened.Overdue(ctx)
+	assert.Nil(t, err)
+	if assert.Len(t, overdue, 1) {
+		assert.Equal(t, "202102268484913", overdue[0].InterchangeID)
+		assert.Equal(t, standard850V1.EnvelopeHeaderV2.ReceiverID, overdue[0].ReceiverID)
+	}
+
+}
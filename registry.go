@@ -0,0 +1,65 @@
+package easi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// documentTypes holds a constructor for every document type by its Go type
+// name, so that documents and their schemas can be looked up by name.
+var documentTypes = map[string]func() Document{
+	"Standard180V1":      func() Document { return &Standard180V1{} },
+	"Standard214V1":      func() Document { return &Standard214V1{} },
+	"Standard812V1":      func() Document { return &Standard812V1{} },
+	"Standard820V1":      func() Document { return &Standard820V1{} },
+	"Standard824V1":      func() Document { return &Standard824V1{} },
+	"Standard832V1":      func() Document { return &Standard832V1{} },
+	"Standard846V3":      func() Document { return &Standard846V3{} },
+	"Standard850V1":      func() Document { return &Standard850V1{} },
+	"Standard850V4":      func() Document { return &Standard850V4{} },
+	"Standard850V4Batch": func() Document { return &Standard850V4Batch{} },
+	"Standard852V1":      func() Document { return &Standard852V1{} },
+	"Standard856V4":      func() Document { return &Standard856V4{} },
+	"Standard856V5":      func() Document { return &Standard856V5{} },
+	"Standard856V7":      func() Document { return &Standard856V7{} },
+	"Standard870V1":      func() Document { return &Standard870V1{} },
+	"Standard940V1":      func() Document { return &Standard940V1{} },
+	"Standard940V2":      func() Document { return &Standard940V2{} },
+	"Standard943V1":      func() Document { return &Standard943V1{} },
+	"Standard947V1":      func() Document { return &Standard947V1{} },
+	"Standard997V1":      func() Document { return &Standard997V1{} },
+	"Standard997V2":      func() Document { return &Standard997V2{} },
+	"Standard997V3":      func() Document { return &Standard997V3{} },
+}
+
+// RegisterDocumentType adds a document type, or replaces the one registered
+// under the same name. It is not safe to call while documents are being looked
+// up.
+func RegisterDocumentType(name string, newDocument func() Document) {
+
+	documentTypes[name] = newDocument
+}
+
+// DocumentTypes returns the names of the registered document types in order.
+func DocumentTypes() []string {
+
+	var names []string
+	for name := range documentTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// NewDocument returns an empty document of a registered type, ready for
+// FromBytes or to be filled in and written with ToBytes.
+func NewDocument(name string) (Document, error) {
+
+	newDocument, ok := documentTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("document type %s is not registered", name)
+	}
+
+	return newDocument(), nil
+}
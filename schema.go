@@ -0,0 +1,371 @@
+package easi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// jsonSchema is the draft-07 subset the generator writes.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	MinItems             int                    `json:"minItems,omitempty"`
+	MinLength            int                    `json:"minLength,omitempty"`
+	MaxLength            int                    `json:"maxLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	Definitions          map[string]*jsonSchema `json:"definitions,omitempty"`
+}
+
+// schemaField is the record layout of a field. Required fields must be
+// present and not empty, and Prep fields are filled in by Prep so a payload
+// can leave them out. Max lengths are those of the X12 004010 element the
+// field is carried in.
+type schemaField struct {
+	Description string
+	Required    bool
+	Prep        bool
+	MaxLength   int
+	Pattern     string
+	Enum        []string
+}
+
+// schemaFields holds the record layouts by field name, or by record type and
+// field name where a field means something else in one record. Fields that
+// are not listed are described from their name. Code enums are the code lists
+// kept with each document's constants.
+var schemaFields = map[string]schemaField{
+
+	// Envelope
+	"SenderQualifier":      {Description: "Interchange ID qualifier of the sender.", MaxLength: 2},
+	"SenderID":             {Description: "Interchange ID of the sender.", Required: true, MaxLength: 15},
+	"ReceiverQualifier":    {Description: "Interchange ID qualifier of the receiver.", MaxLength: 2},
+	"ReceiverID":           {Description: "Interchange ID of the receiver.", Required: true, MaxLength: 15},
+	"FileCreationDate":     {Description: "Date the file was written.", Prep: true},
+	"FileCreationTime":     {Description: "Time the file was written.", Prep: true},
+	"ProductionOrTest":     {Description: "Whether the file is production or test data.", Enum: []string{"P", "T"}},
+	"InterchangeID":        {Description: "Control number of the interchange, echoed in its trailer and acknowledgement."},
+	"RoutingTrailerRecord": {Description: "Envelope trailer record identifier.", Prep: true},
+	"NumberOfDocuments":    {Description: "Number of documents in the envelope.", Prep: true},
+
+	"EnvelopeHeaderV2.TimeZone": {Description: "Time zone of the file creation time.", Prep: true},
+	"EnvelopeHeaderV3.TimeZone": {Description: "Time zone of the file creation time.", Prep: true},
+
+	// Records
+	"Header":             {Description: "Record identifier.", Prep: true},
+	"TransactionType":    {Description: "Transaction set identifier.", Prep: true},
+	"VersionNumber":      {Description: "Version of the record layout.", Prep: true},
+	"DetailSectionLoopA": {Description: "Detail record identifier.", Prep: true},
+	"DetailSectionLoopB": {Description: "Detail record identifier.", Prep: true},
+	"OtherChargesRecord": {Description: "Other charges record identifier.", Prep: true},
+	"PalletRecord":       {Description: "Pallet record identifier.", Prep: true},
+	"LocationRecord":     {Description: "Location record identifier.", Prep: true},
+	"TrailerRecord":      {Description: "Trailer record identifier.", Prep: true},
+	"RecordCount":        {Description: "Number of detail records in the document.", Prep: true},
+
+	"Standard850V4Transaction.PODate":                     {Description: "Date of the purchase order, set to the day it is written.", Prep: true},
+	"Standard850V4LineItem.LineItemNumber":                {Description: "Number of the line within the document.", Prep: true},
+	"Standard850V4LineItem.UnitOrBasisForMeasurementCode": {Description: "Unit the quantities are in, always EA.", Prep: true},
+	"Standard850V4Trailer.TotalQuantityOrdered":           {Description: "Sum of the quantities ordered.", Prep: true},
+	"Standard997V3Document.TransactionType":               {Description: "Transaction set identifier of the acknowledged document.", MaxLength: 3},
+
+	// Transaction
+	"TransactionSetPurpose": {Description: "Original, cancellation or replacement.", Enum: []string{"00", "01", "05"}},
+	"PurchaseOrderTypeCode": {Description: "Type of purchase order, as in SA for stand-alone.", MaxLength: 2},
+	"PurchaseOrderNumber":   {Description: "Purchase order number assigned by the purchaser.", MaxLength: 22},
+	"ReleaseNumber":         {Description: "Release number of a blanket order.", MaxLength: 30},
+	"ContractNumber":        {Description: "Contract number.", MaxLength: 30},
+	"CurrencyCode":          {Description: "ISO 4217 currency code.", MaxLength: 3},
+	"PurchaserAccountID":    {Description: "Account of the purchaser with the vendor.", MaxLength: 80},
+	"VendorID":              {Description: "ID of the vendor.", MaxLength: 80},
+	"StoreID":               {Description: "ID of the store the goods are marked for.", MaxLength: 80},
+	"DistributionCenterID":  {Description: "ID of the distribution center the goods ship to.", MaxLength: 80},
+	"CarrierRoutingDetails": {Description: "Carrier and service, as in UPS Ground.", MaxLength: 35},
+	"CarrierTrackingNumber": {Description: "Tracking number assigned by the carrier.", MaxLength: 30},
+	"BOLNumber":             {Description: "Bill of lading number.", MaxLength: 30},
+	"ShipmentNumber":        {Description: "Shipment identification assigned by the shipper.", MaxLength: 30},
+	"InvoiceNumber":         {Description: "Invoice number.", MaxLength: 22},
+	"MemoNumber":            {Description: "Credit or debit memo number.", Required: true, MaxLength: 22},
+	"CustomerPONumber":      {Description: "Purchase order number of the end customer.", MaxLength: 22},
+	"CreditDebitFlag":       {Description: "Whether the memo credits or debits the purchaser.", Enum: Standard812V1CreditDebitFlags},
+	"DropShipCode":          {Description: "Whether the order ships directly to the end customer.", MaxLength: 1},
+
+	"Standard850V1Transaction.PurchaseOrderNumber": {Description: "Purchase order number assigned by the purchaser.", Required: true, MaxLength: 22},
+	"Standard850V4Transaction.PurchaseOrderNumber": {Description: "Purchase order number assigned by the purchaser.", Required: true, MaxLength: 22},
+	"Standard940V1Transaction.PurchaseOrderNumber": {Description: "Purchase order number of the order to ship.", Required: true, MaxLength: 22},
+	"Standard940V2Transaction.PurchaseOrderNumber": {Description: "Purchase order number of the order to ship.", Required: true, MaxLength: 22},
+	"Standard856V4Transaction.ShipmentNumber":      {Description: "Shipment identification assigned by the shipper.", Required: true, MaxLength: 30},
+	"Standard856V7Transaction.ShipmentNumber":      {Description: "Shipment identification assigned by the shipper.", Required: true, MaxLength: 30},
+	"Standard943V1Transaction.ShipmentNumber":      {Description: "Shipment identification assigned by the shipper.", Required: true, MaxLength: 30},
+
+	// Addresses
+	"DeliverToCompanyName":  {Description: "Company the goods are delivered to.", MaxLength: 60},
+	"DeliverToContactName":  {Description: "Contact the goods are delivered to.", MaxLength: 60},
+	"DeliverToAddress1":     {Description: "First address line of the delivery address.", MaxLength: 55},
+	"DeliverToAddress2":     {Description: "Second address line of the delivery address.", MaxLength: 55},
+	"DeliverToCityName":     {Description: "City of the delivery address.", MaxLength: 30},
+	"DeliverToStateCode":    {Description: "State or province code of the delivery address.", MaxLength: 2},
+	"DeliverToPostalCode":   {Description: "Postal code of the delivery address.", MaxLength: 15},
+	"DeliverToCountryCode":  {Description: "Country code of the delivery address.", MaxLength: 3},
+	"ShipFromCompanyName":   {Description: "Company the goods ship from.", MaxLength: 60},
+	"ShipFromAddress1":      {Description: "First address line of the ship from address.", MaxLength: 55},
+	"ShipFromAddress2":      {Description: "Second address line of the ship from address.", MaxLength: 55},
+	"ShipFromCityName":      {Description: "City of the ship from address.", MaxLength: 30},
+	"ShipFromStateCode":     {Description: "State or province code of the ship from address.", MaxLength: 2},
+	"ShipFromPostalCode":    {Description: "Postal code of the ship from address.", MaxLength: 15},
+	"ShipFromCountryCode":   {Description: "Country code of the ship from address.", MaxLength: 3},
+	"ReturnFromCompanyName": {Description: "Company the goods are returned from.", MaxLength: 60},
+	"ReturnFromContactName": {Description: "Contact the goods are returned from.", MaxLength: 60},
+	"ReturnFromAddress1":    {Description: "First address line of the return from address.", MaxLength: 55},
+	"ReturnFromAddress2":    {Description: "Second address line of the return from address.", MaxLength: 55},
+	"ReturnFromCityName":    {Description: "City of the return from address.", MaxLength: 30},
+	"ReturnFromStateCode":   {Description: "State or province code of the return from address.", MaxLength: 2},
+	"ReturnFromPostalCode":  {Description: "Postal code of the return from address.", MaxLength: 15},
+	"ReturnFromCountryCode": {Description: "Country code of the return from address.", MaxLength: 3},
+
+	// Line Items
+	"LineItemNumber":                {Description: "Number of the line within the document."},
+	"ItemIdentificationGTIN":        {Description: "GTIN-14 of the item.", Required: true, MaxLength: 14, Pattern: "^[0-9]*$"},
+	"MasterStyle":                   {Description: "Style of the item.", MaxLength: 48},
+	"DetailStyle":                   {Description: "Style of the item within its master style.", MaxLength: 48},
+	"ColorCode":                     {Description: "Color of the item."},
+	"SizeCode":                      {Description: "Size of the item."},
+	"UnitOrBasisForMeasurementCode": {Description: "Unit the quantities are in, as in EA for each.", MaxLength: 2},
+	"QuantityOrdered":               {Description: "Quantity ordered.", Required: true},
+	"QuantityShipped":               {Description: "Quantity shipped.", Required: true},
+	"CountryOfOrigin":               {Description: "Country of origin of the item.", MaxLength: 3},
+	"ManufacturersLotID":            {Description: "Lot the item was made in.", MaxLength: 30},
+	"ManufacturersSerialCaseNumber": {Description: "SSCC-18 of the case.", MaxLength: 20},
+	"PalletID":                      {Description: "SSCC-18 of the pallet.", MaxLength: 20},
+
+	// Codes
+	"ReturnActionCode":                  {Description: "Whether the return is requested or authorized.", Enum: Standard180V1Actions},
+	"ReturnReasonCode":                  {Description: "Reason the item is returned.", Required: true, Enum: Standard180V1Reasons},
+	"DispositionCode":                   {Description: "What is done with the returned item.", Enum: Standard180V1Dispositions},
+	"ApplicationAcknowledgementCode":    {Description: "Whether the original document was accepted.", Enum: Standard824V1AcknowledgementCodes},
+	"TransactionSetAcknowledgementCode": {Description: "Whether the acknowledged document was accepted.", Enum: Standard997V3AcknowledgementCodes},
+
+	"Standard214V1Event.StatusCode":                {Description: "Shipment status.", Enum: Standard214V1Statuses},
+	"Standard812V1LineItem.AdjustmentReasonCode":   {Description: "Reason for the adjustment.", Enum: AdjustmentReasons},
+	"Standard820V1Adjustment.AdjustmentReasonCode": {Description: "Reason for the adjustment.", Enum: AdjustmentReasons},
+	"Standard870V1Order.StatusCode":                {Description: "Status of the order.", Enum: Standard870V1Statuses},
+	"Standard870V1LineItem.StatusCode":             {Description: "Status of the line.", Enum: Standard870V1Statuses},
+	"Standard947V1LineItem.AdjustmentReasonCode":   {Description: "Reason for the adjustment.", Enum: Standard947V1Reasons},
+
+	// Lists
+	"Standard850V1.LineItems": {Description: "Lines of the purchase order.", Required: true},
+	"Standard850V4.LineItems": {Description: "Lines of the purchase order.", Required: true},
+	"Standard940V1.LineItems": {Description: "Lines of the warehouse order.", Required: true},
+	"Standard940V2.LineItems": {Description: "Lines of the warehouse order.", Required: true},
+	"Standard180V1.LineItems": {Description: "Lines of the return.", Required: true},
+}
+
+// JSONSchema returns the draft-07 JSON Schema of a registered document type,
+// as its documents are written by encoding/json. Objects allow no other
+// properties, so a misspelt field name fails validation.
+func JSONSchema(ctx context.Context, documentType string) ([]byte, error) {
+
+	document, err := NewDocument(documentType)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := map[string]*jsonSchema{}
+	documentSchema := schemaStruct(reflect.TypeOf(document).Elem(), definitions)
+	documentSchema.Schema = "http://json-schema.org/draft-07/schema#"
+	documentSchema.Title = documentType
+	documentSchema.Description = fmt.Sprintf("EASI %s document.", strings.TrimPrefix(documentType, "Standard"))
+	if len(definitions) > 0 {
+		documentSchema.Definitions = definitions
+	}
+
+	return json.MarshalIndent(documentSchema, "", "  ")
+}
+
+// JSONSchemas returns the JSON Schema of every registered document type by
+// its name.
+func JSONSchemas(ctx context.Context) (map[string][]byte, error) {
+
+	schemas := map[string][]byte{}
+	for _, documentType := range DocumentTypes() {
+		schema, err := JSONSchema(ctx, documentType)
+		if err != nil {
+			return nil, fmt.Errorf("json schema of %s: %w", documentType, err)
+		}
+		schemas[documentType] = schema
+	}
+
+	return schemas, nil
+}
+
+// schemaStruct describes a struct with its fields as properties. Structs met
+// on the way are added to the definitions and referred to.
+func schemaStruct(t reflect.Type, definitions map[string]*jsonSchema) *jsonSchema {
+
+	structSchema := &jsonSchema{
+		Type:                 "object",
+		Properties:           map[string]*jsonSchema{},
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		layout, ok := schemaFields[t.Name()+"."+field.Name]
+		if !ok {
+			layout = schemaFields[field.Name]
+		}
+
+		fieldSchema := schemaType(field.Type, definitions)
+		switch field.Type.Kind() {
+		case reflect.String:
+			schemaString(fieldSchema, field.Name, layout)
+		case reflect.Struct:
+			if fieldSchema.Ref != "" && len(definitions[field.Type.Name()].Required) > 0 {
+				layout.Required = true
+			}
+		case reflect.Slice:
+			if layout.Required {
+				fieldSchema.MinItems = 1
+			}
+		}
+		if fieldSchema.Ref == "" {
+			fieldSchema.Description = layout.Description
+			if fieldSchema.Description == "" {
+				fieldSchema.Description = schemaDescription(t, field)
+			}
+			fieldSchema.ReadOnly = layout.Prep || strings.HasSuffix(field.Name, "Formatted")
+		}
+		if layout.Required && !layout.Prep {
+			structSchema.Required = append(structSchema.Required, name)
+		}
+
+		structSchema.Properties[name] = fieldSchema
+	}
+
+	return structSchema
+}
+
+// schemaType describes a Go type as encoding/json writes it.
+func schemaType(t reflect.Type, definitions map[string]*jsonSchema) *jsonSchema {
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaType(t.Elem(), definitions)
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: "array", Items: schemaType(t.Elem(), definitions)}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: schemaType(t.Elem(), definitions)}
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return &jsonSchema{Type: "string", Format: "date-time"}
+		}
+		if _, ok := definitions[t.Name()]; !ok {
+			definitions[t.Name()] = &jsonSchema{}
+			*definitions[t.Name()] = *schemaStruct(t, definitions)
+		}
+		return &jsonSchema{Ref: "#/definitions/" + t.Name()}
+	}
+
+	return &jsonSchema{}
+}
+
+// schemaString applies the layout of a string field. Dates and times follow
+// the EASI 20060102 and 150405 formats, and fields that are not required may
+// be left empty.
+func schemaString(fieldSchema *jsonSchema, fieldName string, layout schemaField) {
+
+	fieldSchema.MaxLength = layout.MaxLength
+	fieldSchema.Pattern = layout.Pattern
+	switch {
+	case fieldSchema.Pattern != "":
+	case strings.HasSuffix(fieldName, "Date"):
+		fieldSchema.MaxLength = 8
+		fieldSchema.Pattern = "^([0-9]{8})?$"
+	case strings.HasSuffix(fieldName, "Time"):
+		fieldSchema.MaxLength = 6
+		fieldSchema.Pattern = "^([0-9]{4}|[0-9]{6})?$"
+	}
+
+	if layout.Required && !layout.Prep {
+		fieldSchema.MinLength = 1
+	}
+	if len(layout.Enum) > 0 {
+		fieldSchema.Enum = layout.Enum
+		if !layout.Required {
+			fieldSchema.Enum = append([]string{""}, layout.Enum...)
+		}
+	}
+}
+
+// schemaDescription describes a field from its name, as in "Deliver to
+// company name" for DeliverToCompanyName. Amounts carried as cents say so.
+func schemaDescription(t reflect.Type, field reflect.StructField) string {
+
+	var words []string
+	runes := []rune(strings.TrimSuffix(field.Name, "Formatted"))
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) {
+			previous, current := runes[i-1], runes[i]
+			lowerToUpper := unicode.IsUpper(current) && !unicode.IsUpper(previous)
+			acronymEnd := unicode.IsUpper(previous) && unicode.IsUpper(current) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			letterToDigit := unicode.IsDigit(current) != unicode.IsDigit(previous)
+			if !lowerToUpper && !acronymEnd && !letterToDigit {
+				continue
+			}
+		}
+		word := string(runes[start:i])
+		if len(words) > 0 && strings.ToUpper(word) != word {
+			word = strings.ToLower(word)
+		}
+		words = append(words, word)
+		start = i
+	}
+	description := strings.Join(words, " ")
+
+	if strings.HasSuffix(field.Name, "Formatted") {
+		return fmt.Sprintf("%s as written to the file, set by Prep from %s.", description, strings.TrimSuffix(field.Name, "Formatted"))
+	}
+	if _, ok := t.FieldByName(field.Name + "Formatted"); ok && field.Type.Kind() == reflect.Int {
+		return description + " in cents."
+	}
+
+	return description + "."
+}
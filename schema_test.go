@@ -0,0 +1,169 @@
+package easi
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema(t *testing.T) {
+
+	ctx := context.Background()
+
+	schema, err := JSONSchema(ctx, "Standard850V4")
+	assert.Nil(t, err)
+	err = ioutil.WriteFile("./examples/850v4-schema.json", schema, 0644)
+	assert.Nil(t, err)
+
+	var document map[string]interface{}
+	err = json.Unmarshal(schema, &document)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", document["$schema"])
+	assert.Equal(t, false, document["additionalProperties"])
+	assert.ElementsMatch(t, []interface{}{"EnvelopeHeaderV3", "Transaction", "LineItems"}, document["required"])
+
+	definitions := document["definitions"].(map[string]interface{})
+	transaction := definitions["Standard850V4Transaction"].(map[string]interface{})
+	assert.Contains(t, transaction["required"], "PurchaseOrderNumber")
+	properties := transaction["properties"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"", "00", "01", "05"}, properties["TransactionSetPurpose"].(map[string]interface{})["enum"])
+	assert.Equal(t, float64(2), properties["DeliverToStateCode"].(map[string]interface{})["maxLength"])
+	assert.Equal(t, "^([0-9]{8})?$", properties["CancelDate"].(map[string]interface{})["pattern"])
+	assert.Equal(t, true, properties["Header"].(map[string]interface{})["readOnly"])
+
+	lineItem := definitions["Standard850V4LineItem"].(map[string]interface{})
+	properties = lineItem["properties"].(map[string]interface{})
+	assert.Equal(t, "Purchase unit price in cents.", properties["PurchaseUnitPrice"].(map[string]interface{})["description"])
+	assert.Equal(t, float64(14), properties["ItemIdentificationGTIN"].(map[string]interface{})["maxLength"])
+
+	// Payload
+	payload, err := json.Marshal(Standard850V4s[0])
+	assert.Nil(t, err)
+	var value map[string]interface{}
+	err = json.Unmarshal(payload, &value)
+	assert.Nil(t, err)
+	assert.Empty(t, schemaUndeclared(document, definitions, value, ""))
+
+	value["Transaction"].(map[string]interface{})["PurchaseOrderNo"] = "12345678"
+	assert.Equal(t, []string{"Transaction.PurchaseOrderNo"}, schemaUndeclared(document, definitions, value, ""))
+
+	_, err = JSONSchema(ctx, "Standard999V1")
+	assert.NotNil(t, err)
+
+}
+
+func TestJSONSchemas(t *testing.T) {
+
+	ctx := context.Background()
+
+	RegisterDocumentType("Standard850V4Copy", func() Document { return &Standard850V4{} })
+	defer delete(documentTypes, "Standard850V4Copy")
+	assert.Contains(t, DocumentTypes(), "Standard850V4Copy")
+
+	schemas, err := JSONSchemas(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, schemas, len(DocumentTypes()))
+	for documentType, schema := range schemas {
+		var document map[string]interface{}
+		err := json.Unmarshal(schema, &document)
+		assert.Nil(t, err, documentType)
+		assert.Equal(t, documentType, document["title"])
+		assert.Equal(t, "object", document["type"])
+	}
+
+	var document map[string]interface{}
+	err = json.Unmarshal(schemas["Standard870V1"], &document)
+	assert.Nil(t, err)
+	order := document["definitions"].(map[string]interface{})["Standard870V1Order"].(map[string]interface{})
+	statusCode := order["properties"].(map[string]interface{})["StatusCode"].(map[string]interface{})
+	assert.Contains(t, statusCode["enum"], Standard870V1StatusBackordered)
+	assert.Contains(t, statusCode["enum"], Standard870V1StatusPicked)
+
+	document = nil
+	err = json.Unmarshal(schemas["Standard997V3"], &document)
+	assert.Nil(t, err)
+	acknowledgedDocument := document["definitions"].(map[string]interface{})["Standard997V3Document"].(map[string]interface{})
+	transactionType := acknowledgedDocument["properties"].(map[string]interface{})["TransactionType"].(map[string]interface{})
+	assert.Nil(t, transactionType["readOnly"])
+
+}
+
+func TestJSONSchemaEnums(t *testing.T) {
+
+	ctx := context.Background()
+
+	schemas, err := JSONSchemas(ctx)
+	assert.Nil(t, err)
+
+	for _, enum := range []struct {
+		documentType string
+		definition   string
+		property     string
+		codes        []string
+		constants    []string
+	}{
+		{"Standard180V1", "Standard180V1Transaction", "ReturnActionCode", Standard180V1Actions, []string{Standard180V1ActionRequest, Standard180V1ActionAuthorization}},
+		{"Standard180V1", "Standard180V1LineItem", "ReturnReasonCode", Standard180V1Reasons, []string{Standard180V1ReasonDamaged, Standard180V1ReasonDefective, Standard180V1ReasonWrongItem, Standard180V1ReasonOverstock}},
+		{"Standard180V1", "Standard180V1LineItem", "DispositionCode", Standard180V1Dispositions, []string{Standard180V1DispositionRestock, Standard180V1DispositionRepair, Standard180V1DispositionDestroy}},
+		{"Standard214V1", "Standard214V1Event", "StatusCode", Standard214V1Statuses, []string{Standard214V1StatusPickedUp, Standard214V1StatusInTransit, Standard214V1StatusDelivered, Standard214V1StatusException}},
+		{"Standard812V1", "Standard812V1Transaction", "CreditDebitFlag", Standard812V1CreditDebitFlags, []string{Standard812V1Credit, Standard812V1Debit}},
+		{"Standard812V1", "Standard812V1LineItem", "AdjustmentReasonCode", AdjustmentReasons, []string{AdjustmentReasonPricingError, AdjustmentReasonItemNotAcceptedDamaged, AdjustmentReasonQuantityContested, AdjustmentReasonIncorrectProduct}},
+		{"Standard820V1", "Standard820V1Adjustment", "AdjustmentReasonCode", AdjustmentReasons, []string{AdjustmentReasonPricingError, AdjustmentReasonItemNotAcceptedDamaged, AdjustmentReasonQuantityContested, AdjustmentReasonIncorrectProduct}},
+		{"Standard824V1", "Standard824V1Transaction", "ApplicationAcknowledgementCode", Standard824V1AcknowledgementCodes, []string{Standard824V1Accepted, Standard824V1AcceptedWithErrors, Standard824V1Rejected}},
+		{"Standard870V1", "Standard870V1Order", "StatusCode", Standard870V1Statuses, []string{Standard870V1StatusOpen, Standard870V1StatusPicked, Standard870V1StatusShipped, Standard870V1StatusCancelled, Standard870V1StatusBackordered}},
+		{"Standard870V1", "Standard870V1LineItem", "StatusCode", Standard870V1Statuses, []string{Standard870V1StatusOpen, Standard870V1StatusPicked, Standard870V1StatusShipped, Standard870V1StatusCancelled, Standard870V1StatusBackordered}},
+		{"Standard947V1", "Standard947V1LineItem", "AdjustmentReasonCode", Standard947V1Reasons, []string{Standard947V1ReasonPhysicalCount, Standard947V1ReasonDamagedInFacility, Standard947V1ReasonDamagedInTransit, Standard947V1ReasonProductRecall}},
+		{"Standard997V3", "Standard997V3Document", "TransactionSetAcknowledgementCode", Standard997V3AcknowledgementCodes, []string{Standard997V3Accepted, Standard997V3AcceptedWithErrors, Standard997V3PartiallyAccepted, Standard997V3Rejected}},
+	} {
+		assert.ElementsMatch(t, enum.constants, enum.codes, enum.property)
+
+		var document map[string]interface{}
+		err := json.Unmarshal(schemas[enum.documentType], &document)
+		assert.Nil(t, err)
+		definition := document["definitions"].(map[string]interface{})[enum.definition].(map[string]interface{})
+		property := definition["properties"].(map[string]interface{})[enum.property].(map[string]interface{})
+
+		var values []string
+		for _, value := range property["enum"].([]interface{}) {
+			if value != "" {
+				values = append(values, value.(string))
+			}
+		}
+		assert.Equal(t, enum.codes, values, enum.definition+"."+enum.property)
+	}
+
+}
+
+// schemaUndeclared lists the properties of a payload its schema does not
+// declare.
+func schemaUndeclared(schema map[string]interface{}, definitions map[string]interface{}, value interface{}, path string) []string {
+
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{})
+	}
+
+	var undeclared []string
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range value {
+			propertySchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				undeclared = append(undeclared, strings.TrimPrefix(path+"."+name, "."))
+				continue
+			}
+			undeclared = append(undeclared, schemaUndeclared(propertySchema, definitions, property, path+"."+name)...)
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for _, item := range value {
+			undeclared = append(undeclared, schemaUndeclared(items, definitions, item, path)...)
+		}
+	}
+
+	return undeclared
+}
@@ -1,5 +1,47 @@
 package easi
 
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Adjustment reason codes are shared by the 812 and the 820.
+const (
+	AdjustmentReasonPricingError           = "01"
+	AdjustmentReasonItemNotAcceptedDamaged = "04"
+	AdjustmentReasonQuantityContested      = "06"
+	AdjustmentReasonIncorrectProduct       = "07"
+)
+
+// AdjustmentReasons lists the adjustment reason codes.
+var AdjustmentReasons = []string{AdjustmentReasonPricingError, AdjustmentReasonItemNotAcceptedDamaged, AdjustmentReasonQuantityContested, AdjustmentReasonIncorrectProduct}
+
 type Header struct {
 	Header string 
-}
\ No newline at end of file
+}
+
+// Document is implemented by every standard so it can be carried in an
+// interchange.
+type Document interface {
+	Prep(ctx context.Context) (error)
+	ToBytes(ctx context.Context) (*[]byte, error)
+	FromBytes(ctx context.Context, req []byte) (error)
+}
+
+// Amounts are carried as signed cents and written with four decimals.
+func parseAmount(req string) (int, error) {
+
+	amount, err := strconv.ParseFloat(req, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(math.Round(amount * 100)), nil
+}
+
+func formatAmount(amount int) string {
+
+	return fmt.Sprintf("%.4f", float64(amount) / 100)
+}
@@ -0,0 +1,69 @@
+package easi
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+)
+
+const (
+	ValidationCodeMissing     = "MISSING"
+	ValidationCodeInvalid     = "INVALID"
+	ValidationCodeDuplicate   = "DUPLICATE"
+	ValidationCodeUnknownGTIN = "UNKNOWN_GTIN"
+	ValidationCodeClosedStore = "CLOSED_STORE"
+	ValidationCodeParse       = "PARSE"
+	ValidationCodeOther       = "OTHER"
+)
+
+type ValidationReport struct {
+	InterchangeID       string
+	TransactionType     string
+	PurchaseOrderNumber string
+	Errors              []ValidationError
+}
+
+type ValidationError struct {
+	PurchaseOrderNumber string
+	RecordType          string
+	LineItemNumber      int
+	Field               string
+	Code                string
+	Message             string
+	Line                int
+	Column              int
+}
+
+func (s *ValidationReport) Valid() bool {
+
+	return len(s.Errors) == 0
+}
+
+// AddParseError records an error returned while parsing the document. The line
+// and column are kept when the file itself is malformed.
+func (s *ValidationReport) AddParseError(ctx context.Context, err error) {
+
+	validationError := ValidationError{
+		PurchaseOrderNumber: s.PurchaseOrderNumber,
+		Code:                ValidationCodeParse,
+		Message:             err.Error(),
+	}
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		validationError.Line = parseError.Line
+		validationError.Column = parseError.Column
+		validationError.Message = parseError.Err.Error()
+	}
+
+	s.Errors = append(s.Errors, validationError)
+}
+
+func (s ValidationError) Error() string {
+
+	if s.LineItemNumber > 0 {
+		return fmt.Sprintf("record %s line %d %s: %s %s", s.RecordType, s.LineItemNumber, s.Field, s.Code, s.Message)
+	}
+
+	return fmt.Sprintf("record %s %s: %s %s", s.RecordType, s.Field, s.Code, s.Message)
+}
@@ -0,0 +1,240 @@
+package easi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// X12Options configures an ANSI X12 004010 interchange. Zero values take the
+// defaults: "*" between elements, ":" between sub-elements, "~" after each
+// segment and control numbers of 1. Sender and receiver default to the EASI
+// envelope, and the usage indicator to its ProductionOrTest.
+type X12Options struct {
+	ElementSeparator            byte
+	SubElementSeparator         byte
+	SegmentTerminator           string
+	InterchangeControlNumber    int
+	GroupControlNumber          int
+	TransactionSetControlNumber int
+	SenderQualifier             string
+	SenderID                    string
+	ReceiverQualifier           string
+	ReceiverID                  string
+	UsageIndicator              string
+}
+
+type x12Writer struct {
+	options  X12Options
+	segments []string
+	count    int
+	err      error
+}
+
+// prep fills in the defaults, taking the sender, receiver and usage indicator
+// from the EASI envelope.
+func (s *X12Options) prep(envelopeHeader EnvelopeHeaderV3) {
+
+	if s.ElementSeparator == 0 {
+		s.ElementSeparator = '*'
+	}
+	if s.SubElementSeparator == 0 {
+		s.SubElementSeparator = ':'
+	}
+	if s.SegmentTerminator == "" {
+		s.SegmentTerminator = "~"
+	}
+	if s.InterchangeControlNumber <= 0 {
+		s.InterchangeControlNumber = 1
+	}
+	if s.GroupControlNumber <= 0 {
+		s.GroupControlNumber = 1
+	}
+	if s.TransactionSetControlNumber <= 0 {
+		s.TransactionSetControlNumber = 1
+	}
+	if s.SenderQualifier == "" {
+		s.SenderQualifier = envelopeHeader.SenderQualifier
+	}
+	if s.SenderID == "" {
+		s.SenderID = envelopeHeader.SenderID
+	}
+	if s.ReceiverQualifier == "" {
+		s.ReceiverQualifier = envelopeHeader.ReceiverQualifier
+	}
+	if s.ReceiverID == "" {
+		s.ReceiverID = envelopeHeader.ReceiverID
+	}
+	if s.UsageIndicator == "" {
+		s.UsageIndicator = envelopeHeader.ProductionOrTest
+	}
+}
+
+// header writes the ISA, GS and ST segments. Dates are EASI 20060102 dates
+// and times at least 1504.
+func (s *x12Writer) header(functionalIdentifierCode string, transactionSetIdentifierCode string, date string, time string) {
+
+	if len(date) != 8 || len(time) < 4 {
+		s.fail(fmt.Errorf("x12 interchange date %q and time %q", date, time))
+		return
+	}
+
+	// Interchange
+	isa := []string{
+		"ISA",
+		"00", fmt.Sprintf("%-10s", ""),
+		"00", fmt.Sprintf("%-10s", ""),
+		fmt.Sprintf("%-2s", s.options.SenderQualifier), fmt.Sprintf("%-15s", s.options.SenderID),
+		fmt.Sprintf("%-2s", s.options.ReceiverQualifier), fmt.Sprintf("%-15s", s.options.ReceiverID),
+		date[2:], time[:4],
+		"U", "00401",
+		fmt.Sprintf("%09d", s.options.InterchangeControlNumber),
+		"0", s.options.UsageIndicator,
+		string(s.options.SubElementSeparator),
+	}
+	for _, element := range isa[1:16] {
+		s.check(element)
+	}
+	if len(isa[6]) != 15 || len(isa[8]) != 15 || len(isa[13]) != 9 {
+		s.fail(fmt.Errorf("x12 ISA sender, receiver or control number too long"))
+	}
+	s.segments = append(s.segments, strings.Join(isa, string(s.options.ElementSeparator)))
+
+	// Functional Group
+	s.segment("GS", functionalIdentifierCode, strings.TrimSpace(s.options.SenderID), strings.TrimSpace(s.options.ReceiverID), date, time[:4], strconv.Itoa(s.options.GroupControlNumber), "X", "004010")
+
+	// Transaction Set
+	s.count = 0
+	s.segment("ST", transactionSetIdentifierCode, s.transactionSetControlNumber())
+}
+
+// trailer writes the SE, GE and IEA segments for a single transaction set.
+func (s *x12Writer) trailer() {
+
+	s.segment("SE", strconv.Itoa(s.count+1), s.transactionSetControlNumber())
+	s.segment("GE", "1", strconv.Itoa(s.options.GroupControlNumber))
+	s.segment("IEA", "1", fmt.Sprintf("%09d", s.options.InterchangeControlNumber))
+}
+
+// segment writes a segment without its trailing empty elements.
+func (s *x12Writer) segment(elements ...string) {
+
+	for len(elements) > 1 && elements[len(elements)-1] == "" {
+		elements = elements[:len(elements)-1]
+	}
+	for _, element := range elements {
+		s.check(element)
+	}
+	s.segments = append(s.segments, strings.Join(elements, string(s.options.ElementSeparator)))
+	s.count++
+}
+
+// check fails on data that holds one of the delimiters, as X12 004010 has no
+// release character to escape it with. A line break after the segment
+// terminator is only layout, unless the line break is the terminator itself.
+func (s *x12Writer) check(element string) {
+
+	segmentTerminator := strings.TrimSpace(s.options.SegmentTerminator)
+	if segmentTerminator == "" {
+		segmentTerminator = s.options.SegmentTerminator
+	}
+	if strings.IndexByte(element, s.options.ElementSeparator) >= 0 ||
+		strings.IndexByte(element, s.options.SubElementSeparator) >= 0 ||
+		strings.Contains(element, segmentTerminator) {
+		s.fail(fmt.Errorf("x12 element %q contains a delimiter", element))
+	}
+}
+
+func (s *x12Writer) fail(err error) {
+
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *x12Writer) transactionSetControlNumber() string {
+
+	return fmt.Sprintf("%04d", s.options.TransactionSetControlNumber)
+}
+
+func (s *x12Writer) bytes() (*[]byte, error) {
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	byteArray := []byte(strings.Join(s.segments, s.options.SegmentTerminator) + s.options.SegmentTerminator)
+
+	return &byteArray, nil
+}
+
+// x12Amount writes cents as an X12 decimal without trailing zeros.
+func x12Amount(amount int) string {
+
+	return strconv.FormatFloat(float64(amount)/100, 'f', -1, 64)
+}
+
+// X12Segment is one segment of an X12 interchange. Elements leaves out the
+// segment ID, and Position counts segments from 1 at the ISA.
+type X12Segment struct {
+	ID       string
+	Elements []string
+	Position int
+}
+
+// Element returns the element at its X12 reference number, as in 3 for BEG03,
+// or "" when the segment is shorter.
+func (s *X12Segment) Element(number int) string {
+
+	if number < 1 || number > len(s.Elements) {
+		return ""
+	}
+
+	return s.Elements[number-1]
+}
+
+// ParseX12 splits an interchange into segments. The delimiters are taken from
+// the fixed width ISA segment, and line breaks after segment terminators are
+// ignored.
+func ParseX12(ctx context.Context, req []byte) ([]X12Segment, X12Options, error) {
+
+	var options X12Options
+
+	req = bytes.TrimLeft(req, " \t\r\n")
+	if len(req) < 106 || string(req[:3]) != "ISA" {
+		return nil, options, fmt.Errorf("x12 interchange does not start with an ISA segment")
+	}
+	options.ElementSeparator = req[3]
+	options.SubElementSeparator = req[104]
+	options.SegmentTerminator = string(req[105])
+
+	var segments []X12Segment
+	for _, segment := range strings.Split(string(req), options.SegmentTerminator) {
+		segment = strings.Trim(segment, "\r\n")
+		if segment == "" {
+			continue
+		}
+		elements := strings.Split(segment, string(options.ElementSeparator))
+		segments = append(segments, X12Segment{
+			ID:       elements[0],
+			Elements: elements[1:],
+			Position: len(segments) + 1,
+		})
+	}
+
+	// Interchange
+	isa := segments[0]
+	if len(isa.Elements) < 16 {
+		return nil, options, fmt.Errorf("x12 ISA segment has %d elements", len(isa.Elements))
+	}
+	options.SenderQualifier = strings.TrimSpace(isa.Element(5))
+	options.SenderID = strings.TrimSpace(isa.Element(6))
+	options.ReceiverQualifier = strings.TrimSpace(isa.Element(7))
+	options.ReceiverID = strings.TrimSpace(isa.Element(8))
+	options.InterchangeControlNumber, _ = strconv.Atoi(isa.Element(13))
+	options.UsageIndicator = isa.Element(15)
+
+	return segments, options, nil
+}
@@ -0,0 +1,301 @@
+package easi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ToX12 renders the purchase order as an X12 004010 850 interchange. The
+// distribution center is the ship to party and the store the mark for party.
+// Other charges become header SAC segments, and color and size each a PID
+// segment after their PO1.
+func (s *Standard850V4) ToX12(ctx context.Context, options X12Options) (*[]byte, error) {
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+	options.prep(s.EnvelopeHeaderV3)
+
+	w := x12Writer{options: options}
+	w.header("PO", "850", s.EnvelopeHeaderV3.FileCreationDate, s.EnvelopeHeaderV3.FileCreationTime)
+
+	// Beginning Segment
+	w.segment("BEG", s.Transaction.TransactionSetPurpose, s.Transaction.PurchaseOrderTypeCode, s.Transaction.PurchaseOrderNumber, s.Transaction.ReleaseNumber, s.Transaction.PODate, s.Transaction.ContractNumber)
+
+	// Currency
+	if s.Transaction.CurrencyCode != "" {
+		w.segment("CUR", "BY", s.Transaction.CurrencyCode)
+	}
+
+	// References
+	if s.Transaction.VendorID != "" {
+		w.segment("REF", "IA", s.Transaction.VendorID)
+	}
+	if s.Transaction.PurchaserAccountID != "" {
+		w.segment("REF", "IT", s.Transaction.PurchaserAccountID)
+	}
+	if s.Transaction.CustomerPONumber != "" {
+		w.segment("REF", "CO", s.Transaction.CustomerPONumber)
+	}
+
+	// Other Charges
+	for _, otherCharge := range s.OtherCharges {
+		w.segment("SAC", "C", "ZZZZ", "", "", strconv.Itoa(otherCharge.OtherChargeAmount), "", "", "", "", "", "", "", "", "", otherCharge.OtherChargeDescription)
+	}
+
+	// Dates
+	if s.Transaction.RequestedShipDate != "" {
+		w.segment("DTM", "010", s.Transaction.RequestedShipDate)
+	}
+	if s.Transaction.CancelDate != "" {
+		w.segment("DTM", "001", s.Transaction.CancelDate)
+	}
+
+	// Ship To
+	var identificationCodeQualifier string
+	if s.Transaction.DistributionCenterID != "" {
+		identificationCodeQualifier = "92"
+	}
+	w.segment("N1", "ST", s.Transaction.DeliverToCompanyName, identificationCodeQualifier, s.Transaction.DistributionCenterID)
+	if s.Transaction.DeliverToContactName != "" {
+		w.segment("N2", s.Transaction.DeliverToContactName)
+	}
+	if s.Transaction.DeliverToAddress1 != "" || s.Transaction.DeliverToAddress2 != "" {
+		w.segment("N3", s.Transaction.DeliverToAddress1, s.Transaction.DeliverToAddress2)
+	}
+	w.segment("N4", s.Transaction.DeliverToCityName, s.Transaction.DeliverToStateCode, s.Transaction.DeliverToPostalCode, s.Transaction.DeliverToCountryCode)
+
+	// Mark For
+	if s.Transaction.StoreID != "" {
+		w.segment("N1", "Z7", "", "92", s.Transaction.StoreID)
+	}
+
+	// Line Items
+	for _, lineItem := range s.LineItems {
+		po1 := []string{"PO1", strconv.Itoa(lineItem.LineItemNumber), strconv.Itoa(lineItem.QuantityOrdered), lineItem.UnitOrBasisForMeasurementCode, x12Amount(lineItem.PurchaseUnitPrice), ""}
+		if lineItem.ItemIdentificationGTIN != "" {
+			po1 = append(po1, "UK", lineItem.ItemIdentificationGTIN)
+		}
+		if lineItem.MasterStyle != "" {
+			po1 = append(po1, "VA", lineItem.MasterStyle)
+		}
+		w.segment(po1...)
+		if lineItem.ColorCode != "" {
+			w.segment("PID", "F", "73", "", "", lineItem.ColorCode)
+		}
+		if lineItem.SizeCode != "" {
+			w.segment("PID", "F", "74", "", "", lineItem.SizeCode)
+		}
+	}
+
+	// Transaction Totals
+	w.segment("CTT", strconv.Itoa(len(s.LineItems)), strconv.Itoa(s.Trailer.TotalQuantityOrdered))
+
+	w.trailer()
+
+	return w.bytes()
+}
+
+// FromX12 reads an X12 850 interchange holding one purchase order, the
+// reverse of ToX12. The segments it has no field for are returned rather than
+// dropped, along with those of any N1 loop other than the ship to and mark for
+// parties and any header segment repeated within a loop.
+func (s *Standard850V4) FromX12(ctx context.Context, req []byte) ([]X12Segment, error) {
+
+	segments, options, err := ParseX12(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Envelope
+	s.EnvelopeHeaderV3.SenderQualifier = options.SenderQualifier
+	s.EnvelopeHeaderV3.SenderID = options.SenderID
+	s.EnvelopeHeaderV3.ReceiverQualifier = options.ReceiverQualifier
+	s.EnvelopeHeaderV3.ReceiverID = options.ReceiverID
+	s.EnvelopeHeaderV3.ProductionOrTest = options.UsageIndicator
+	s.EnvelopeHeaderV3.InterchangeID = strconv.Itoa(options.InterchangeControlNumber)
+	s.EnvelopeTrailerV3.InterchangeID = s.EnvelopeHeaderV3.InterchangeID
+
+	var unmapped []X12Segment
+	var transactionSets int
+	var loop string
+	for _, segment := range segments {
+
+		// Loops
+		switch segment.ID {
+		case "N1":
+			loop = "N1" + segment.Element(1)
+		case "PO1":
+			loop = "PO1"
+		case "ST", "CTT", "SE":
+			loop = ""
+		}
+		if loop != "" {
+			switch segment.ID {
+			case "CUR", "REF", "SAC", "DTM":
+				unmapped = append(unmapped, segment)
+				continue
+			}
+		}
+
+		// Build
+		mapped := true
+		switch segment.ID {
+		case "ISA", "GE", "IEA", "SE":
+		case "GS":
+			s.EnvelopeHeaderV3.FileCreationDate = segment.Element(4)
+			s.EnvelopeHeaderV3.FileCreationTime = segment.Element(5)
+		case "ST":
+			if segment.Element(1) != "850" {
+				return nil, fmt.Errorf("x12 transaction set %s at segment %d is not an 850", segment.Element(1), segment.Position)
+			}
+			transactionSets++
+			if transactionSets > 1 {
+				return nil, fmt.Errorf("x12 interchange holds more than one 850")
+			}
+		case "BEG":
+			s.Transaction.TransactionSetPurpose = segment.Element(1)
+			s.Transaction.PurchaseOrderTypeCode = segment.Element(2)
+			s.Transaction.PurchaseOrderNumber = segment.Element(3)
+			s.Transaction.ReleaseNumber = segment.Element(4)
+			s.Transaction.PODate = segment.Element(5)
+			s.Transaction.ContractNumber = segment.Element(6)
+		case "CUR":
+			mapped = segment.Element(1) == "BY"
+			if mapped {
+				s.Transaction.CurrencyCode = segment.Element(2)
+			}
+		case "REF":
+			switch segment.Element(1) {
+			case "IA":
+				s.Transaction.VendorID = segment.Element(2)
+			case "IT":
+				s.Transaction.PurchaserAccountID = segment.Element(2)
+			case "CO":
+				s.Transaction.CustomerPONumber = segment.Element(2)
+			default:
+				mapped = false
+			}
+		case "SAC":
+			mapped = segment.Element(1) == "C"
+			if mapped {
+				amount, err := strconv.Atoi(segment.Element(5))
+				if err != nil {
+					return nil, fmt.Errorf("x12 SAC05 at segment %d: %w", segment.Position, err)
+				}
+				s.OtherCharges = append(s.OtherCharges, Standard850V4OtherCharge{
+					LineItemNumberForOtherCharges: len(s.OtherCharges) + 1,
+					OtherChargeDescription:        segment.Element(15),
+					OtherChargeAmount:             amount,
+				})
+			}
+		case "DTM":
+			switch segment.Element(1) {
+			case "010":
+				s.Transaction.RequestedShipDate = segment.Element(2)
+			case "001":
+				s.Transaction.CancelDate = segment.Element(2)
+			default:
+				mapped = false
+			}
+		case "N1":
+			switch loop {
+			case "N1ST":
+				s.Transaction.DeliverToCompanyName = segment.Element(2)
+				s.Transaction.DistributionCenterID = segment.Element(4)
+			case "N1Z7":
+				s.Transaction.StoreID = segment.Element(4)
+			default:
+				mapped = false
+			}
+		case "N2":
+			mapped = loop == "N1ST"
+			if mapped {
+				s.Transaction.DeliverToContactName = segment.Element(1)
+			}
+		case "N3":
+			mapped = loop == "N1ST"
+			if mapped {
+				s.Transaction.DeliverToAddress1 = segment.Element(1)
+				s.Transaction.DeliverToAddress2 = segment.Element(2)
+			}
+		case "N4":
+			mapped = loop == "N1ST"
+			if mapped {
+				s.Transaction.DeliverToCityName = segment.Element(1)
+				s.Transaction.DeliverToStateCode = segment.Element(2)
+				s.Transaction.DeliverToPostalCode = segment.Element(3)
+				s.Transaction.DeliverToCountryCode = segment.Element(4)
+			}
+		case "PO1":
+			var x Standard850V4LineItem
+			err := x.FromX12(ctx, segment)
+			if err != nil {
+				return nil, err
+			}
+			s.LineItems = append(s.LineItems, x)
+		case "PID":
+			mapped = loop == "PO1" && segment.Element(1) == "F"
+			if mapped {
+				lineItem := &s.LineItems[len(s.LineItems)-1]
+				switch segment.Element(2) {
+				case "73":
+					lineItem.ColorCode = segment.Element(5)
+				case "74":
+					lineItem.SizeCode = segment.Element(5)
+				default:
+					mapped = false
+				}
+			}
+		case "CTT":
+			s.Trailer.RecordCount, _ = strconv.Atoi(segment.Element(1))
+			s.Trailer.TotalQuantityOrdered, _ = strconv.Atoi(segment.Element(2))
+		default:
+			mapped = false
+		}
+		if !mapped {
+			unmapped = append(unmapped, segment)
+		}
+	}
+	if transactionSets == 0 {
+		return nil, fmt.Errorf("x12 interchange holds no 850")
+	}
+
+	return unmapped, nil
+}
+
+// FromX12 reads a PO1 segment. Product IDs are read from the UK, UP and EN
+// qualifiers for the GTIN and VA for the master style.
+func (s *Standard850V4LineItem) FromX12(ctx context.Context, segment X12Segment) error {
+
+	s.LineItemNumber, _ = strconv.Atoi(segment.Element(1))
+	if segment.Element(2) != "" {
+		quantityOrdered, err := strconv.ParseFloat(segment.Element(2), 64)
+		if err != nil {
+			return fmt.Errorf("x12 PO102 at segment %d: %w", segment.Position, err)
+		}
+		s.QuantityOrdered = int(math.Round(quantityOrdered))
+	}
+	s.UnitOrBasisForMeasurementCode = segment.Element(3)
+	if segment.Element(4) != "" {
+		purchaseUnitPrice, err := parseAmount(segment.Element(4))
+		if err != nil {
+			return fmt.Errorf("x12 PO104 at segment %d: %w", segment.Position, err)
+		}
+		s.PurchaseUnitPrice = purchaseUnitPrice
+	}
+	for number := 6; number < len(segment.Elements); number += 2 {
+		switch segment.Element(number) {
+		case "UK", "UP", "EN":
+			s.ItemIdentificationGTIN = segment.Element(number + 1)
+		case "VA":
+			s.MasterStyle = segment.Element(number + 1)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package easi
+
+import (
+	"context"
+	"strconv"
+)
+
+// ToX12 renders the ship notice as an X12 004010 856 interchange in the
+// shipment, order, tare, pack, item hierarchy. Shipments are grouped into
+// orders by purchase order number, and a pallet holding cartons of several
+// orders appears as a tare under each of them. Pallet IDs and carton case
+// numbers are written as MAN GM SSCC marks, and weights in pounds.
+func (s *Standard856V7) ToX12(ctx context.Context, options X12Options) (*[]byte, error) {
+
+	// Prep
+	errPrep := s.Prep(ctx)
+	if errPrep != nil {
+		return nil, errPrep
+	}
+	options.prep(s.EnvelopeHeaderV3)
+
+	w := x12Writer{options: options}
+	w.header("SH", "856", s.EnvelopeHeaderV3.FileCreationDate, s.EnvelopeHeaderV3.FileCreationTime)
+
+	// Beginning Segment
+	var asnTime string
+	if len(s.Transaction.ASNTime) >= 4 {
+		asnTime = s.Transaction.ASNTime[:4]
+	}
+	w.segment("BSN", s.Transaction.TransactionSetPurpose, s.Transaction.ShipmentNumber, s.Transaction.ASNDate, asnTime, "0001")
+
+	// Orders
+	var purchaseOrderNumbers []string
+	var caseCount int
+	var grossWeight float64
+	orders := map[string]Standard856V7Shipment{}
+	for _, pallet := range s.Pallets {
+		for _, shipment := range pallet.Shipments {
+			purchaseOrderNumber := standard856V7PurchaseOrderNumber(shipment)
+			if _, ok := orders[purchaseOrderNumber]; !ok {
+				orders[purchaseOrderNumber] = shipment
+				purchaseOrderNumbers = append(purchaseOrderNumbers, purchaseOrderNumber)
+			}
+			caseCount++
+			grossWeight += shipment.CaseWeight
+		}
+	}
+
+	// Shipment
+	var hierarchicalIDs int
+	hl := func(parentID int, levelCode string, children bool) int {
+		hierarchicalIDs++
+		var parent string
+		if parentID > 0 {
+			parent = strconv.Itoa(parentID)
+		}
+		childCode := "0"
+		if children {
+			childCode = "1"
+		}
+		w.segment("HL", strconv.Itoa(hierarchicalIDs), parent, levelCode, childCode)
+		return hierarchicalIDs
+	}
+	shipmentID := hl(0, "S", len(purchaseOrderNumbers) > 0)
+	w.segment("TD1", "CTN25", strconv.Itoa(caseCount), "", "", "", "G", x12Weight(grossWeight), "LB")
+	if s.Transaction.CarrierRoutingDetails != "" {
+		w.segment("TD5", "", "", "", "", s.Transaction.CarrierRoutingDetails)
+	}
+	if s.Transaction.TrailerID != "" {
+		w.segment("TD3", "TL", "", s.Transaction.TrailerID)
+	}
+	if s.Transaction.BOLNumber != "" {
+		w.segment("REF", "BM", s.Transaction.BOLNumber)
+	}
+	if s.Transaction.ShipmentDate != "" {
+		w.segment("DTM", "011", s.Transaction.ShipmentDate)
+	}
+
+	// Ship To
+	var identificationCodeQualifier string
+	if s.Transaction.DistributionCenterID != "" {
+		identificationCodeQualifier = "92"
+	}
+	w.segment("N1", "ST", s.Transaction.DeliverToCompanyName, identificationCodeQualifier, s.Transaction.DistributionCenterID)
+	if s.Transaction.DeliverToContactName != "" {
+		w.segment("N2", s.Transaction.DeliverToContactName)
+	}
+	if s.Transaction.DeliverToAddress1 != "" || s.Transaction.DeliverToAddress2 != "" {
+		w.segment("N3", s.Transaction.DeliverToAddress1, s.Transaction.DeliverToAddress2)
+	}
+	w.segment("N4", s.Transaction.DeliverToCityName, s.Transaction.DeliverToStateCode, s.Transaction.DeliverToPostalCode, s.Transaction.DeliverToCountryCode)
+
+	// Mark For
+	if s.Transaction.StoreID != "" {
+		w.segment("N1", "Z7", "", "92", s.Transaction.StoreID)
+	}
+
+	for _, purchaseOrderNumber := range purchaseOrderNumbers {
+
+		// Order
+		first := orders[purchaseOrderNumber]
+		orderID := hl(shipmentID, "O", true)
+		w.segment("PRF", purchaseOrderNumber, "", "", first.PODate, "", "", first.PurchaseOrderTypeCode)
+		if s.Transaction.VendorID != "" {
+			w.segment("REF", "IA", s.Transaction.VendorID)
+		}
+		if first.ManufacturersOrderNumber != "" {
+			w.segment("REF", "VN", first.ManufacturersOrderNumber)
+		}
+
+		for _, pallet := range s.Pallets {
+			var shipments []Standard856V7Shipment
+			for _, shipment := range pallet.Shipments {
+				if standard856V7PurchaseOrderNumber(shipment) == purchaseOrderNumber {
+					shipments = append(shipments, shipment)
+				}
+			}
+			if len(shipments) == 0 {
+				continue
+			}
+
+			// Tare
+			tareID := hl(orderID, "T", true)
+			if pallet.PalletID != "" {
+				w.segment("MAN", "GM", pallet.PalletID)
+			}
+
+			for _, shipment := range shipments {
+
+				// Pack
+				packID := hl(tareID, "P", len(shipment.LineItems) > 0)
+				if shipment.CaseWeight > 0 {
+					w.segment("TD1", "CTN25", "1", "", "", "", "G", x12Weight(shipment.CaseWeight), "LB")
+				}
+				if shipment.CarrierTrackingNumber != "" {
+					w.segment("REF", "CN", shipment.CarrierTrackingNumber)
+				}
+				if shipment.ManufacturersSerialCaseNumber != "" {
+					w.segment("MAN", "GM", shipment.ManufacturersSerialCaseNumber)
+				}
+
+				for _, lineItem := range shipment.LineItems {
+
+					// Item
+					hl(packID, "I", false)
+					lin := []string{"LIN", strconv.Itoa(lineItem.LineItemNumber)}
+					if lineItem.ItemIdentificationGTIN != "" {
+						lin = append(lin, "UK", lineItem.ItemIdentificationGTIN)
+					}
+					if lineItem.MasterStyle != "" {
+						lin = append(lin, "VA", lineItem.MasterStyle)
+					}
+					if lin[1] == "0" {
+						lin[1] = ""
+					}
+					w.segment(lin...)
+					w.segment("SN1", "", strconv.Itoa(lineItem.QuantityShipped), lineItem.UnitOrBasisForMeasurementCode)
+					if lineItem.ColorCode != "" {
+						w.segment("PID", "F", "73", "", "", lineItem.ColorCode)
+					}
+					if lineItem.SizeCode != "" {
+						w.segment("PID", "F", "74", "", "", lineItem.SizeCode)
+					}
+				}
+			}
+		}
+	}
+
+	// Transaction Totals
+	w.segment("CTT", strconv.Itoa(hierarchicalIDs))
+
+	w.trailer()
+
+	return w.bytes()
+}
+
+// standard856V7PurchaseOrderNumber falls back to the purchase order number of
+// the first line item for a shipment without one.
+func standard856V7PurchaseOrderNumber(shipment Standard856V7Shipment) string {
+
+	if shipment.BuyersPurchaseOrderNumber == "" && len(shipment.LineItems) > 0 {
+		return shipment.LineItems[0].BuyersPurchaseOrderNumber
+	}
+
+	return shipment.BuyersPurchaseOrderNumber
+}
+
+func x12Weight(weight float64) string {
+
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
@@ -0,0 +1,181 @@
+package easi
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandard850V4ToX12(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard850V4 := range Standard850V4s {
+		byteArrayPointer, err := standard850V4.ToX12(ctx, X12Options{
+			SegmentTerminator:        "~\n",
+			InterchangeControlNumber: 905,
+			GroupControlNumber:       12,
+		})
+		assert.Nil(t, err)
+		if byteArrayPointer != nil {
+			err := ioutil.WriteFile("./examples/850x12-0.txt", *byteArrayPointer, 0644)
+			assert.Nil(t, err)
+
+			segments := strings.Split(strings.TrimSuffix(string(*byteArrayPointer), "~\n"), "~\n")
+			if assert.True(t, len(segments) > 4) {
+				assert.Len(t, segments[0], 105)
+				assert.True(t, strings.HasSuffix(segments[0], "*000000905*0*T*:"))
+				assert.Equal(t, "ST*850*0001", segments[2])
+				assert.Equal(t, "BEG*00*SA*12345678**"+standard850V4.Transaction.PODate, segments[3])
+				assert.Contains(t, segments, "SAC*C*ZZZZ***200")
+				assert.Contains(t, segments, "PO1*2*6*EA*1.85**UK*00821780002799")
+				assert.Contains(t, segments, "CTT*2*18")
+				assert.Equal(t, "SE*14*0001", segments[len(segments)-3])
+				assert.Equal(t, "GE*1*12", segments[len(segments)-2])
+				assert.Equal(t, "IEA*1*000000905", segments[len(segments)-1])
+			}
+		}
+
+		// Newline Terminator
+		byteArrayPointer, err = standard850V4.ToX12(ctx, X12Options{SegmentTerminator: "\n"})
+		assert.Nil(t, err)
+		if byteArrayPointer != nil {
+			segments := strings.Split(strings.TrimSuffix(string(*byteArrayPointer), "\n"), "\n")
+			assert.Equal(t, "ST*850*0001", segments[2])
+
+			var roundTrip Standard850V4
+			unmapped, err := roundTrip.FromX12(ctx, *byteArrayPointer)
+			assert.Nil(t, err)
+			assert.Empty(t, unmapped)
+			assert.Equal(t, standard850V4.Transaction.PurchaseOrderNumber, roundTrip.Transaction.PurchaseOrderNumber)
+		}
+
+		standard850V4.Transaction.DeliverToAddress2 = "Room 237\n"
+		_, err = standard850V4.ToX12(ctx, X12Options{SegmentTerminator: "\n"})
+		assert.NotNil(t, err)
+
+		standard850V4.Transaction.DeliverToAddress2 = "Room 237*"
+		_, err = standard850V4.ToX12(ctx, X12Options{})
+		assert.NotNil(t, err)
+	}
+
+}
+
+func TestStandard850V4FromX12(t *testing.T) {
+
+	ctx := context.Background()
+
+	bytes, readErr := ioutil.ReadFile("./examples/850x12-0.txt")
+	if readErr != nil {
+		assert.Nil(t, readErr)
+	}
+
+	var standard850V4 Standard850V4
+	unmapped, err := standard850V4.FromX12(ctx, bytes)
+	assert.Nil(t, err)
+	assert.Empty(t, unmapped)
+
+	assert.Equal(t, "905", standard850V4.EnvelopeHeaderV3.InterchangeID)
+	assert.Equal(t, "383601069", standard850V4.EnvelopeHeaderV3.SenderID)
+	assert.Equal(t, Standard850V4s[0].Transaction.PurchaseOrderNumber, standard850V4.Transaction.PurchaseOrderNumber)
+	assert.Equal(t, Standard850V4s[0].Transaction.DistributionCenterID, standard850V4.Transaction.DistributionCenterID)
+	assert.Equal(t, Standard850V4s[0].Transaction.DeliverToContactName, standard850V4.Transaction.DeliverToContactName)
+	assert.Equal(t, 18, standard850V4.Trailer.TotalQuantityOrdered)
+	if assert.Len(t, standard850V4.LineItems, 2) {
+		assert.Equal(t, "00821780002799", standard850V4.LineItems[1].ItemIdentificationGTIN)
+		assert.Equal(t, 185, standard850V4.LineItems[1].PurchaseUnitPrice)
+	}
+	if assert.Len(t, standard850V4.OtherCharges, 1) {
+		assert.Equal(t, 200, standard850V4.OtherCharges[0].OtherChargeAmount)
+	}
+
+}
+
+func TestStandard850V4FromX12Unmapped(t *testing.T) {
+
+	ctx := context.Background()
+
+	x12 := strings.Join([]string{
+		"ISA|00|          |00|          |ZZ|PARTNER        |01|383601069      |210226|1200|U|00401|000000042|0|P|>",
+		"GS|PO|PARTNER|383601069|20210226|1200|42|X|004010",
+		"ST|850|0001",
+		"BEG|00|SA|PO-42||20210226",
+		"REF|DP|0012",
+		"N1|BT|Overlook Accounts Payable",
+		"N3|PO Box 1",
+		"N1|ST|Overlook Hotel|92|05",
+		"N4|Estes Park|CO|80517|US",
+		"N1|Z7||92|8976",
+		"PO1|1|3|EA|12.5||UP|082178000266|VA|345345",
+		"PID|F|73|||NAVY",
+		"PID|F|08|||Knit Cap",
+		"REF|ZZ|line note",
+		"CTT|1|3",
+		"SE|14|0001",
+		"GE|1|42",
+		"IEA|1|000000042",
+	}, "\\\r\n") + "\\\r\n"
+
+	var standard850V4 Standard850V4
+	unmapped, err := standard850V4.FromX12(ctx, []byte(x12))
+	assert.Nil(t, err)
+
+	var unmappedIDs []string
+	for _, segment := range unmapped {
+		unmappedIDs = append(unmappedIDs, segment.ID+segment.Element(1)+segment.Element(2))
+	}
+	assert.Equal(t, []string{"REFDP0012", "N1BTOverlook Accounts Payable", "N3PO Box 1", "PIDF08", "REFZZline note"}, unmappedIDs)
+
+	assert.Equal(t, "P", standard850V4.EnvelopeHeaderV3.ProductionOrTest)
+	assert.Equal(t, "8976", standard850V4.Transaction.StoreID)
+	assert.Equal(t, "US", standard850V4.Transaction.DeliverToCountryCode)
+	if assert.Len(t, standard850V4.LineItems, 1) {
+		assert.Equal(t, "082178000266", standard850V4.LineItems[0].ItemIdentificationGTIN)
+		assert.Equal(t, "NAVY", standard850V4.LineItems[0].ColorCode)
+		assert.Equal(t, 1250, standard850V4.LineItems[0].PurchaseUnitPrice)
+	}
+
+	_, err = standard850V4.FromX12(ctx, []byte("GS|PO"))
+	assert.NotNil(t, err)
+
+}
+
+func TestStandard856V7ToX12(t *testing.T) {
+
+	ctx := context.Background()
+
+	for _, standard856V7 := range Standard856V7s {
+		standard856V7.Transaction.BOLNumber = "BOL12345"
+		byteArrayPointer, err := standard856V7.ToX12(ctx, X12Options{
+			SegmentTerminator: "~\n",
+		})
+		assert.Nil(t, err)
+		if byteArrayPointer != nil {
+			err := ioutil.WriteFile("./examples/856x12-0.txt", *byteArrayPointer, 0644)
+			assert.Nil(t, err)
+
+			segments := strings.Split(strings.TrimSuffix(string(*byteArrayPointer), "~\n"), "~\n")
+			var hl []string
+			for _, segment := range segments {
+				if strings.HasPrefix(segment, "HL*") {
+					hl = append(hl, segment)
+				}
+			}
+			assert.Equal(t, []string{"HL*1**S*1", "HL*2*1*O*1", "HL*3*2*T*1", "HL*4*3*P*1", "HL*5*4*I*0", "HL*6*4*I*0"}, hl)
+			assert.Contains(t, segments, "REF*BM*BOL12345")
+			assert.Contains(t, segments, "TD1*CTN25*1****G*12*LB")
+			assert.Contains(t, segments, "MAN*GM*123456789123456789")
+			assert.Contains(t, segments, "MAN*GM*345345")
+			assert.Contains(t, segments, "PRF*34534534***20210224***SA")
+			assert.Contains(t, segments, "SN1**6*23")
+			assert.Contains(t, segments, "CTT*6")
+		}
+
+		_, err = standard856V7.ToX12(ctx, X12Options{SegmentTerminator: "\n"})
+		assert.Nil(t, err)
+	}
+
+}